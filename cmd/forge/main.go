@@ -0,0 +1,157 @@
+// Command forge provides tools for working with forge config files.
+//
+// Usage:
+//
+//	forge diff [-format human|json] <old.cfg> <new.cfg>
+//	forge explain <file.cfg> <key>
+//
+// The diff command prints every value which was added, removed, modified or
+// changed type between the two config files. It exits with 0 when there are no
+// changes, 1 when there are changes and 2 when an error occurred.
+//
+// The explain command prints the value of the key (e.g. "primary.host") and where
+// it was set, followed by every earlier definition it overrode, including those in
+// included files. It exits with 0 when the key exists and 2 otherwise.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/brettlangdon/forge"
+)
+
+const usage = `usage: forge <command> [arguments]
+
+commands:
+  diff     print the changes between two config files
+  explain  print where a key was set and the definitions it overrode
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var code int
+	switch os.Args[1] {
+	case "diff":
+		code = runDiff(os.Args[2:], os.Stdout, os.Stderr)
+	case "explain":
+		code = runExplain(os.Args[2:], os.Stdout, os.Stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "forge: unknown command '%s'\n\n%s", os.Args[1], usage)
+		code = 2
+	}
+	os.Exit(code)
+}
+
+func runDiff(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "human", "output format, either 'human' or 'json'")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: forge diff [-format human|json] <old.cfg> <new.cfg>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || (*format != "human" && *format != "json") {
+		flags.Usage()
+		return 2
+	}
+
+	oldSettings, err := forge.ParseFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "forge: %s: %s\n", flags.Arg(0), err)
+		return 2
+	}
+	newSettings, err := forge.ParseFile(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "forge: %s: %s\n", flags.Arg(1), err)
+		return 2
+	}
+
+	changes := forge.Diff(oldSettings, newSettings)
+	if *format == "json" {
+		if changes == nil {
+			changes = []forge.Change{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "forge: %s\n", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		for _, change := range changes {
+			fmt.Fprintln(stdout, change)
+		}
+	}
+
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runExplain(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: forge explain <file.cfg> <key>")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	settings, err := forge.ParseFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "forge: %s: %s\n", flags.Arg(0), err)
+		return 2
+	}
+	value, err := settings.Resolve(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "forge: %s\n", err)
+		return 2
+	}
+	provenance, err := settings.Provenance(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "forge: %s\n", err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "%s = %s\n", flags.Arg(1), formatValue(value))
+	fmt.Fprintf(stdout, "  set at %s\n", formatPosition(provenance.Position))
+	for _, position := range provenance.Overridden {
+		fmt.Fprintf(stdout, "  overrides %s\n", formatPosition(position))
+	}
+	return 0
+}
+
+// formatValue will format value as JSON
+func formatValue(value forge.Value) string {
+	data, err := forge.EncodeJSON(value)
+	if err != nil {
+		return fmt.Sprintf("<%s>", value.GetType())
+	}
+	return string(data)
+}
+
+func formatPosition(position forge.Position) string {
+	if position.IsValid() == false {
+		return "<unknown>"
+	}
+	return position.String()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const oldConfig = `host = "a";
+primary { host = "localhost"; port = 5432; }
+alias = primary;
+`
+
+const newConfig = `host = "b";
+port = 1;
+primary { host = "localhost"; port = 5432; }
+alias = primary;
+`
+
+// writeConfigs will write the old and new configs to a temporary directory, the returned func removes it
+func writeConfigs(t *testing.T) (string, string, func()) {
+	dir, err := ioutil.TempDir("", "forge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldFile := filepath.Join(dir, "old.cfg")
+	newFile := filepath.Join(dir, "new.cfg")
+	if err = ioutil.WriteFile(oldFile, []byte(oldConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(newFile, []byte(newConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return oldFile, newFile, func() { os.RemoveAll(dir) }
+}
+
+func TestDiffHuman(t *testing.T) {
+	t.Parallel()
+
+	oldFile, newFile, cleanup := writeConfigs(t)
+	defer cleanup()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDiff([]string{oldFile, newFile}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 got %d (%s)", code, stderr.String())
+	}
+	expected := "~ host: \"a\" -> \"b\"\n+ port = 1\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q got %q", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runDiff([]string{oldFile, oldFile}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("expected exit code 0 without output got %d %q", code, stdout.String())
+	}
+	if code := runDiff([]string{oldFile, oldFile + ".missing"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for a missing file got %d", code)
+	}
+	if code := runDiff([]string{"-format", "xml", oldFile, newFile}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for an unknown format got %d", code)
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	t.Parallel()
+
+	oldFile, newFile, cleanup := writeConfigs(t)
+	defer cleanup()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDiff([]string{"-format", "json", oldFile, newFile}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 got %d (%s)", code, stderr.String())
+	}
+	var changes []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &changes); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes got %v", changes)
+	}
+	if changes[0]["path"] != "host" || changes[0]["kind"] != "modified" || changes[0]["old"] != "a" || changes[0]["new"] != "b" {
+		t.Errorf("unexpected change %v", changes[0])
+	}
+	if changes[1]["path"] != "port" || changes[1]["kind"] != "added" || changes[1]["new"] != float64(1) {
+		t.Errorf("unexpected change %v", changes[1])
+	}
+
+	stdout.Reset()
+	if code := runDiff([]string{"-format", "json", oldFile, oldFile}, &stdout, &stderr); code != 0 || strings.TrimSpace(stdout.String()) != "[]" {
+		t.Errorf("expected exit code 0 with an empty list got %d %q", code, stdout.String())
+	}
+}
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	oldFile, _, cleanup := writeConfigs(t)
+	defer cleanup()
+
+	var stdout, stderr bytes.Buffer
+	if code := runExplain([]string{oldFile, "alias"}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0 got %d (%s)", code, stderr.String())
+	}
+	expected := "alias = {\"host\":\"localhost\",\"port\":5432}\n  set at " + oldFile + " line 3 column 1\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q got %q", expected, stdout.String())
+	}
+
+	if code := runExplain([]string{oldFile, "missing"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for a missing key got %d", code)
+	}
+}
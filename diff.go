@@ -0,0 +1,159 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ChangeKind is an int type for representing the kinds of changes reported by `Diff`
+type ChangeKind int
+
+const (
+	// ChangeAdded is a value which only exists in the new Section
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved is a value which only exists in the old Section
+	ChangeRemoved
+	// ChangeModified is a value which has the same type but a different value
+	ChangeModified
+	// ChangeTypeChanged is a value which has a different type
+	ChangeTypeChanged
+)
+
+var changeKinds = [...]string{
+	ChangeAdded:       "added",
+	ChangeRemoved:     "removed",
+	ChangeModified:    "modified",
+	ChangeTypeChanged: "type-changed",
+}
+
+func (kind ChangeKind) String() string {
+	if 0 <= kind && kind < ChangeKind(len(changeKinds)) {
+		return changeKinds[kind]
+	}
+	return "unknown"
+}
+
+// MarshalText will encode the kind as its name (e.g. "added")
+func (kind ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(kind.String()), nil
+}
+
+// Change describes a single difference between two Sections found by `Diff`
+type Change struct {
+	// Path is the path of the value which changed (e.g. "primary.hosts[1]")
+	Path string
+	// Kind is the kind of change
+	Kind ChangeKind
+	// Old is the value in the old Section, it is nil for added values
+	Old Value
+	// New is the value in the new Section, it is nil for removed values
+	New Value
+}
+
+func (change Change) String() string {
+	switch change.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s = %s", change.Path, formatChangeValue(change.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s = %s", change.Path, formatChangeValue(change.Old))
+	case ChangeTypeChanged:
+		return fmt.Sprintf(
+			"~ %s: %s %s -> %s %s", change.Path,
+			change.Old.GetType(), formatChangeValue(change.Old),
+			change.New.GetType(), formatChangeValue(change.New),
+		)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", change.Path, formatChangeValue(change.Old), formatChangeValue(change.New))
+}
+
+// MarshalJSON will encode the change as an object with "path", "kind", "old" and "new" keys
+func (change Change) MarshalJSON() ([]byte, error) {
+	encoded := struct {
+		Path string          `json:"path"`
+		Kind ChangeKind      `json:"kind"`
+		Old  json.RawMessage `json:"old,omitempty"`
+		New  json.RawMessage `json:"new,omitempty"`
+	}{Path: change.Path, Kind: change.Kind}
+
+	var err error
+	if change.Old != nil {
+		if encoded.Old, err = EncodeJSON(change.Old); err != nil {
+			return nil, err
+		}
+	}
+	if change.New != nil {
+		if encoded.New, err = EncodeJSON(change.New); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+// formatChangeValue will format value as JSON for displaying a Change
+func formatChangeValue(value Value) string {
+	encoded, err := EncodeJSON(value)
+	if err != nil {
+		return fmt.Sprintf("<%s>", value.GetType())
+	}
+	return string(encoded)
+}
+
+// Diff will compare the old and new Sections and respond with every value which was added,
+// removed, modified or changed type. Sections are compared key by key and Lists element by
+// element, an added or removed Section or List is reported as a single Change.
+// References are compared by name without being resolved
+func Diff(oldSection *Section, newSection *Section) []Change {
+	var changes []Change
+	diffValues(nil, oldSection, newSection, &changes)
+	return changes
+}
+
+// diffValues will append the differences between oldValue and newValue found at path to changes
+func diffValues(path []pathSegment, oldValue Value, newValue Value, changes *[]Change) {
+	if oldValue.GetType() != newValue.GetType() {
+		*changes = append(*changes, Change{Path: formatPath(path), Kind: ChangeTypeChanged, Old: oldValue, New: newValue})
+		return
+	}
+
+	switch before := oldValue.(type) {
+	case *Section:
+		after := newValue.(*Section)
+		for _, key := range before.Keys() {
+			keyPath := appendPath(path, pathSegment{key: key})
+			newChild, ok := after.values[key]
+			if ok == false {
+				*changes = append(*changes, Change{Path: formatPath(keyPath), Kind: ChangeRemoved, Old: before.values[key]})
+				continue
+			}
+			diffValues(keyPath, before.values[key], newChild, changes)
+		}
+		for _, key := range after.Keys() {
+			if _, ok := before.values[key]; ok == false {
+				keyPath := appendPath(path, pathSegment{key: key})
+				*changes = append(*changes, Change{Path: formatPath(keyPath), Kind: ChangeAdded, New: after.values[key]})
+			}
+		}
+	case *List:
+		after := newValue.(*List)
+		for i := 0; i < before.Length() || i < after.Length(); i++ {
+			indexPath := appendPath(path, pathSegment{index: i, isIndex: true})
+			switch {
+			case i >= after.Length():
+				*changes = append(*changes, Change{Path: formatPath(indexPath), Kind: ChangeRemoved, Old: before.values[i]})
+			case i >= before.Length():
+				*changes = append(*changes, Change{Path: formatPath(indexPath), Kind: ChangeAdded, New: after.values[i]})
+			default:
+				diffValues(indexPath, before.values[i], after.values[i], changes)
+			}
+		}
+	default:
+		if oldValue.Equal(newValue) == false {
+			*changes = append(*changes, Change{Path: formatPath(path), Kind: ChangeModified, Old: oldValue, New: newValue})
+		}
+	}
+}
+
+// appendPath will return a copy of path with segment appended to it
+func appendPath(path []pathSegment, segment pathSegment) []pathSegment {
+	return append(append(make([]pathSegment, 0, len(path)+1), path...), segment)
+}
@@ -0,0 +1,84 @@
+package forge_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldSettings, err := forge.ParseString(`
+same = "value";
+changed = 1;
+retyped = "1";
+removed = true;
+list = [1, 2, 3];
+primary {
+  host = "localhost";
+  ref = .host;
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSettings, err := forge.ParseString(`
+same = "value";
+changed = 2;
+retyped = 1;
+list = [1, 4];
+primary {
+  host = "localhost";
+  ref = .other;
+  other = "remote";
+}
+added { key = null; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := forge.Diff(oldSettings, newSettings)
+	expected := []string{
+		"~ changed: 1 -> 2",
+		"~ list[1]: 2 -> 4",
+		"- list[2] = 3",
+		`~ primary.ref: "localhost" -> "remote"`,
+		`+ primary.other = "remote"`,
+		"- removed = true",
+		`~ retyped: STRING "1" -> INTEGER 1`,
+		`+ added = {"key":null}`,
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes got %d: %v", len(expected), len(changes), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("expected '%s' got '%s'", expected[i], change.String())
+		}
+	}
+
+	if changes[0].Kind != forge.ChangeModified || changes[0].Path != "changed" {
+		t.Errorf("unexpected change %#v", changes[0])
+	}
+	if changes[2].Kind != forge.ChangeRemoved || changes[2].New != nil {
+		t.Errorf("unexpected change %#v", changes[2])
+	}
+	if changes[6].Kind != forge.ChangeTypeChanged || changes[6].Old.GetType() != forge.STRING {
+		t.Errorf("unexpected change %#v", changes[6])
+	}
+
+	data, err := json.Marshal(changes[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"path":"primary.other","kind":"added","new":"remote"}` {
+		t.Errorf("unexpected JSON %s", string(data))
+	}
+
+	if changes = forge.Diff(oldSettings, oldSettings); len(changes) != 0 {
+		t.Errorf("expected no changes got %v", changes)
+	}
+}
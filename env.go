@@ -0,0 +1,160 @@
+package forge
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvError describes an environment variable which could not be applied by `Section.ApplyEnv`, it wraps Err
+type EnvError struct {
+	// Variable is the name of the environment variable (e.g. "MYAPP__DB__PORT")
+	Variable string
+	// Err is the reason the environment variable could not be applied
+	Err error
+}
+
+func (err *EnvError) Error() string {
+	return fmt.Sprintf("environment variable %s: %s", err.Variable, err.Err)
+}
+
+// Unwrap will return the reason the environment variable could not be applied
+func (err *EnvError) Unwrap() error {
+	return err.Err
+}
+
+// EnvErrors is a list of every EnvError found by `Section.ApplyEnv`
+type EnvErrors []*EnvError
+
+func (errs EnvErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ApplyEnv will override the settings in this Section with every environment variable which starts
+// with prefix followed by separator. The rest of the name is split on separator into lowercase keys
+// (e.g. with the prefix "MYAPP" and separator "__", MYAPP__DB__PORT overrides db.port).
+//
+// Values are parsed like the value of a setting, so numbers, booleans, null, quoted strings and
+// lists keep their types (e.g. `5433`, `true` or `["a", "b"]`), any other value is used as a string.
+// Missing Sections and keys are created, use `ApplyEnvStrict` to only override existing keys.
+//
+// Will respond with EnvErrors and leave this Section unchanged if any environment variable
+// cannot be applied
+func (section *Section) ApplyEnv(prefix string, separator string) error {
+	return section.applyEnv(os.Environ(), prefix, separator, false)
+}
+
+// ApplyEnvStrict will override the settings in this Section with environment variables like `ApplyEnv`,
+// except every environment variable for a key which does not exist yet is reported as an EnvError
+// wrapping a *NotFoundError (e.g. a typo like MYAPP__DB__PROT)
+func (section *Section) ApplyEnvStrict(prefix string, separator string) error {
+	return section.applyEnv(os.Environ(), prefix, separator, true)
+}
+
+func (section *Section) applyEnv(environ []string, prefix string, separator string, strict bool) error {
+	overrides, variables, err := envSection(environ, prefix, separator)
+	if err != nil {
+		return err
+	}
+
+	var errs EnvErrors
+	section.checkOverrides(overrides, nil, variables, strict, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return section.Merge(overrides)
+}
+
+// checkOverrides will add an EnvError to errs for every value of overrides which cannot be merged
+// into this Section, or which does not exist yet when strict is true
+func (section *Section) checkOverrides(
+	overrides *Section, path []pathSegment, variables map[string]string, strict bool, errs *EnvErrors,
+) {
+	for _, key := range overrides.OrderedKeys() {
+		keyPath := appendPath(path, pathSegment{key: key})
+		override := overrides.values[key]
+		target, ok := section.values[key]
+
+		var err error
+		switch {
+		case ok == false:
+			if strict {
+				resolved := formatPath(sectionPath(section))
+				err = newNotFoundError(section, absolutePath(section, EscapeKey(key)), resolved, key)
+			}
+		case override.GetType() == SECTION && target.GetType() == SECTION:
+			target.(*Section).checkOverrides(override.(*Section), keyPath, variables, strict, errs)
+		case mergeKind(override) != mergeKind(target):
+			err = &MergeConflictError{
+				Path:   absolutePath(section, EscapeKey(key)),
+				Source: override.GetType(),
+				Target: target.GetType(),
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		// Report the error for every environment variable at or below the key
+		formatted := formatPath(keyPath)
+		var names []string
+		for variablePath, name := range variables {
+			if variablePath == formatted || strings.HasPrefix(variablePath, formatted+".") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			*errs = append(*errs, &EnvError{Variable: name, Err: err})
+		}
+	}
+}
+
+// envSection will create a Section from every environment variable in environ ("NAME=value") which
+// starts with prefix followed by separator, see `Section.ApplyEnv`. It also responds with the name
+// of the environment variable for the path of every value
+func envSection(environ []string, prefix string, separator string) (*Section, map[string]string, error) {
+	section := NewSection()
+	variables := make(map[string]string)
+
+	// Sort the variables so conflicts are reported the same way every time
+	environ = append(make([]string, 0, len(environ)), environ...)
+	sort.Strings(environ)
+
+	var errs EnvErrors
+	for _, variable := range environ {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) != 2 || strings.HasPrefix(parts[0], prefix+separator) == false {
+			continue
+		}
+
+		keys := strings.Split(strings.TrimPrefix(parts[0], prefix+separator), separator)
+		for i, key := range keys {
+			keys[i] = strings.ToLower(key)
+		}
+		if err := section.setKeys(keys, parseEnvValue(parts[1])); err != nil {
+			errs = append(errs, &EnvError{Variable: parts[0], Err: err})
+			continue
+		}
+		variables[JoinPath(keys...)] = parts[0]
+	}
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	return section, variables, nil
+}
+
+// parseEnvValue will parse the value of an environment variable like the value of a
+// setting, falling back to using it as a string
+func parseEnvValue(data string) Value {
+	value, err := parseValue(data)
+	if err != nil {
+		return NewString(data)
+	}
+	return value
+}
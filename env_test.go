@@ -0,0 +1,116 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+const envConfig = `
+db {
+  host = "localhost";
+  port = 5432;
+  replicas = ["a"];
+}
+debug = false;
+`
+
+// DEV: These tests are not run in parallel since they set environment variables
+func TestApplyEnv(t *testing.T) {
+	defer setenv(t, "ENVAPP__DB__PORT", "5433")()
+	defer setenv(t, "ENVAPP__DB__HOST", "10.0.0.1")()
+	defer setenv(t, "ENVAPP__DB__REPLICAS", `["b", "c"]`)()
+	defer setenv(t, "ENVAPP__DEBUG", "TRUE")()
+	defer setenv(t, "ENVAPP__CACHE__TTL", "1.5")()
+	defer setenv(t, "ENVAPP__NAME", `"quoted; string"`)()
+	defer setenv(t, "OTHER__DEBUG", "false")()
+
+	settings, err := forge.ParseString(envConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = settings.ApplyEnv("ENVAPP", "__"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"db.port":   int64(5433),
+		"db.host":   "10.0.0.1",
+		"debug":     true,
+		"cache.ttl": float64(1.5),
+		"name":      "quoted; string",
+	}
+	for path, want := range expected {
+		value, err := settings.Resolve(path)
+		if err != nil {
+			t.Errorf("%s: %s", path, err)
+			continue
+		}
+		if value.GetValue() != want {
+			t.Errorf("expected %s to be %#v got %#v", path, want, value.GetValue())
+		}
+	}
+	replicas, err := settings.ResolveList("db.replicas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strs, err := replicas.StrictStrings()
+	if err != nil || len(strs) != 2 || strs[0] != "b" || strs[1] != "c" {
+		t.Errorf("expected [b c] got %v (%v)", strs, err)
+	}
+}
+
+func TestApplyEnvStrict(t *testing.T) {
+	defer setenv(t, "STRICTAPP_DB_PROT", "5433")()
+	defer setenv(t, "STRICTAPP_DB_HOST", "remote")()
+
+	settings, err := forge.ParseString(envConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = settings.ApplyEnvStrict("STRICTAPP", "_")
+	errs, ok := err.(forge.EnvErrors)
+	if ok == false || len(errs) != 1 {
+		t.Fatalf("expected one EnvError got %v", err)
+	}
+	if errs[0].Variable != "STRICTAPP_DB_PROT" {
+		t.Errorf("expected 'STRICTAPP_DB_PROT' got '%s'", errs[0].Variable)
+	}
+	notFound, ok := errs[0].Err.(*forge.NotFoundError)
+	if ok == false || len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "port" {
+		t.Errorf("expected a *NotFoundError suggesting 'port' got %v", errs[0].Err)
+	}
+
+	// Nothing is applied when any variable cannot be applied
+	host, err := settings.ResolveString("db.host")
+	if err != nil || host != "localhost" {
+		t.Errorf("expected 'localhost' got %s (%v)", host, err)
+	}
+
+	// Strict type conversion does not report new keys
+	settings.SetStrictTypes(true)
+	if err = settings.ApplyEnv("STRICTAPP", "_"); err != nil {
+		t.Fatal(err)
+	}
+	port, err := settings.ResolveInteger("db.prot")
+	if err != nil || port != 5433 {
+		t.Errorf("expected 5433 got %d (%v)", port, err)
+	}
+}
+
+func TestApplyEnvConflict(t *testing.T) {
+	defer setenv(t, "CONFLICTAPP_DB", "remote")()
+
+	settings, err := forge.ParseString(envConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = settings.ApplyEnv("CONFLICTAPP", "_")
+	errs, ok := err.(forge.EnvErrors)
+	if ok == false || len(errs) != 1 {
+		t.Fatalf("expected one EnvError got %v", err)
+	}
+	if _, ok = errs[0].Err.(*forge.MergeConflictError); ok == false {
+		t.Errorf("expected a *MergeConflictError got %v", errs[0].Err)
+	}
+}
@@ -0,0 +1,348 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotExists represents a nonexistent value error
+	ErrNotExists = errors.New("value does not exist")
+	// ErrTypeMismatch represents a value which does not have the requested type
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrIndexOutOfRange represents an index outside of the bounds of a List
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrDanglingReference represents a Reference to a value which cannot be resolved
+	ErrDanglingReference = errors.New("dangling reference")
+	// ErrReferenceCycle represents a Reference which refers back to itself
+	ErrReferenceCycle = errors.New("reference cycle")
+	// ErrMergeConflict represents two values which cannot be merged
+	ErrMergeConflict = errors.New("merge conflict")
+	// ErrFrozen represents a change to a value which was frozen with `Section.Freeze`
+	ErrFrozen = errors.New("value is frozen")
+)
+
+// NotFoundError describes a value which does not exist, it wraps ErrNotExists
+type NotFoundError struct {
+	// Path is the path of the value which was requested (e.g. "primary.sub.key")
+	Path string
+	// Missing is the part of Path which does not exist (e.g. "primary.sub"),
+	// it is empty when the whole Path does not exist
+	Missing string
+	// Resolved is the longest part of Path which does exist (e.g. "primary"),
+	// it is empty when nothing could be resolved past the root section
+	Resolved string
+	// Key is the key which could not be found in the Resolved section (e.g. "sub")
+	Key string
+	// Suggestions are up to three existing keys of the Resolved section which are
+	// closest to Key, ordered by edit distance
+	Suggestions []string
+}
+
+func (err *NotFoundError) Error() string {
+	msg := fmt.Sprintf("value '%s' does not exist", err.Path)
+	if err.Missing != "" && err.Missing != err.Path {
+		msg += fmt.Sprintf(", could not find '%s'", err.Missing)
+	}
+	if len(err.Suggestions) > 0 {
+		quoted := make([]string, len(err.Suggestions))
+		for i, suggestion := range err.Suggestions {
+			quoted[i] = fmt.Sprintf("'%s'", suggestion)
+		}
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(quoted, ", "))
+	}
+	return msg
+}
+
+// Unwrap will return ErrNotExists
+func (err *NotFoundError) Unwrap() error {
+	return ErrNotExists
+}
+
+// TypeError describes a value which could not be used as the requested type, it wraps ErrTypeMismatch
+type TypeError struct {
+	// Path is the path of the value (e.g. "primary.hosts[0]"), it is empty when unknown
+	Path string
+	// Want is the type the value was requested as
+	Want ValueType
+	// Got is the actual type of the value
+	Got ValueType
+}
+
+func (err *TypeError) Error() string {
+	if err.Path == "" {
+		return fmt.Sprintf("cannot convert %s value to %s", err.Got, err.Want)
+	}
+	return fmt.Sprintf("cannot convert %s value at '%s' to %s", err.Got, err.Path, err.Want)
+}
+
+// Unwrap will return ErrTypeMismatch
+func (err *TypeError) Unwrap() error {
+	return ErrTypeMismatch
+}
+
+// IndexError describes an index outside of the bounds of a List, it wraps ErrIndexOutOfRange
+type IndexError struct {
+	// Path is the path of the List (e.g. "primary.hosts"), it is empty when unknown
+	Path   string
+	Index  int
+	Length int
+}
+
+func (err *IndexError) Error() string {
+	if err.Path == "" {
+		return fmt.Sprintf("index %d out of range for list of length %d", err.Index, err.Length)
+	}
+	return fmt.Sprintf("index %d out of range for list '%s' of length %d", err.Index, err.Path, err.Length)
+}
+
+// Unwrap will return ErrIndexOutOfRange
+func (err *IndexError) Unwrap() error {
+	return ErrIndexOutOfRange
+}
+
+// ReferenceError describes a Reference which could not be resolved, either because
+// the value it refers to does not exist or because it is part of a reference cycle.
+// It matches ErrDanglingReference or ErrReferenceCycle and wraps Err
+type ReferenceError struct {
+	// Name is the name of the reference as it was defined (e.g. `primary.sub.key` or `.key`)
+	Name string
+	// Position is where the reference was defined, when it was parsed from a config
+	Position Position
+	// Cycle is the path of every value in a reference cycle, starting and ending with
+	// the same path (e.g. `a`, `b`, `a`), Cycle is empty for dangling references
+	Cycle []string
+	// Err is the reason a dangling reference could not be resolved
+	Err error
+
+	reference *Reference
+}
+
+func (err *ReferenceError) Error() string {
+	location := ""
+	if err.Position.IsValid() {
+		location = fmt.Sprintf(" at %s", err.Position)
+	}
+
+	if len(err.Cycle) > 0 {
+		return fmt.Sprintf("reference '%s'%s is part of a cycle: %s", err.Name, location, strings.Join(err.Cycle, " -> "))
+	}
+	return fmt.Sprintf("reference '%s'%s could not be resolved: %s", err.Name, location, err.Err)
+}
+
+// Is will return true for ErrReferenceCycle when this is a reference cycle,
+// otherwise for ErrDanglingReference
+func (err *ReferenceError) Is(target error) bool {
+	if len(err.Cycle) > 0 {
+		return target == ErrReferenceCycle
+	}
+	return target == ErrDanglingReference
+}
+
+// Unwrap will return the reason a dangling reference could not be resolved
+func (err *ReferenceError) Unwrap() error {
+	return err.Err
+}
+
+// ReferenceErrors is a list of every ReferenceError found by `Section.ValidateReferences`
+type ReferenceErrors []*ReferenceError
+
+func (errs ReferenceErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// MergeConflictError describes two values which cannot be merged, it wraps ErrMergeConflict
+type MergeConflictError struct {
+	// Path is the path of the value in the target Section (e.g. "prod.value")
+	Path string
+	// Source is the type of the value being merged in
+	Source ValueType
+	// Target is the type of the existing value
+	Target ValueType
+}
+
+func (err *MergeConflictError) Error() string {
+	return fmt.Sprintf("source (%v) and target (%v) type doesn't match: %v", err.Source, err.Target, err.Path)
+}
+
+// Unwrap will return ErrMergeConflict
+func (err *MergeConflictError) Unwrap() error {
+	return ErrMergeConflict
+}
+
+// FrozenError describes a change to a frozen value, it wraps ErrFrozen
+type FrozenError struct {
+	// Path is the path of the value which would have changed (e.g. "primary.host"),
+	// it is empty when unknown or for the root Section
+	Path string
+}
+
+func (err *FrozenError) Error() string {
+	if err.Path == "" {
+		return "cannot modify frozen value"
+	}
+	return fmt.Sprintf("cannot modify frozen value '%s'", err.Path)
+}
+
+// Unwrap will return ErrFrozen
+func (err *FrozenError) Unwrap() error {
+	return ErrFrozen
+}
+
+// LayerError describes a layer of `Layers` which could not be loaded or merged, it wraps Err
+type LayerError struct {
+	// Layer is the name of the layer (e.g. "env" or the name of a file)
+	Layer string
+	// Err is the reason the layer could not be loaded or merged
+	Err error
+}
+
+func (err *LayerError) Error() string {
+	return fmt.Sprintf("layer '%s': %s", err.Layer, err.Err)
+}
+
+// Unwrap will return the reason the layer could not be loaded or merged
+func (err *LayerError) Unwrap() error {
+	return err.Err
+}
+
+// wrappedError adds context to the message of an error, the error can still be
+// matched with `errors.Is` and `errors.As`
+type wrappedError struct {
+	context string
+	err     error
+}
+
+func (err *wrappedError) Error() string {
+	return fmt.Sprintf("%s: %s", err.context, err.err)
+}
+
+// Unwrap will return the error the context was added to
+func (err *wrappedError) Unwrap() error {
+	return err.err
+}
+
+// wrapError will prepend the context formatted from format and args to the message of err
+func wrapError(err error, format string, args ...interface{}) error {
+	return &wrappedError{context: fmt.Sprintf(format, args...), err: err}
+}
+
+// prefixErrorPath will prepend prefix to the Path of err if it is one of the errors with a Path
+func prefixErrorPath(err error, prefix string) error {
+	switch err := err.(type) {
+	case *NotFoundError:
+		err.Path = prefix + err.Path
+		if err.Missing != "" {
+			err.Missing = prefix + err.Missing
+		}
+		err.Resolved = strings.TrimSuffix(prefix+err.Resolved, ".")
+	case *TypeError:
+		err.Path = prefix + err.Path
+	case *IndexError:
+		err.Path = prefix + err.Path
+	case *MergeConflictError:
+		err.Path = prefix + err.Path
+	case *FrozenError:
+		err.Path = prefix + err.Path
+	}
+	return err
+}
+
+// newNotFoundError will create a *NotFoundError for key not existing in section, where
+// path is the requested path and resolved is the path of section
+func newNotFoundError(section *Section, path string, resolved string, key string) *NotFoundError {
+	missing := EscapeKey(key)
+	if resolved != "" {
+		missing = resolved + "." + missing
+	}
+	return &NotFoundError{
+		Path:        path,
+		Missing:     missing,
+		Resolved:    resolved,
+		Key:         key,
+		Suggestions: suggestKeys(key, section.Keys(), 3),
+	}
+}
+
+// suggestion is a key which may have been meant instead of a key which does not exist
+type suggestion struct {
+	key      string
+	distance int
+}
+
+// suggestions sorts suggestions by edit distance
+type suggestions []suggestion
+
+func (s suggestions) Len() int           { return len(s) }
+func (s suggestions) Less(i, j int) bool { return s[i].distance < s[j].distance }
+func (s suggestions) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// suggestKeys will return up to limit of the keys which are closest to key by edit distance,
+// keys which share too little with key to be a likely typo are never suggested
+func suggestKeys(key string, keys []string, limit int) []string {
+	var candidates suggestions
+	for _, existing := range keys {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(existing))
+		longest := len([]rune(key))
+		if length := len([]rune(existing)); length > longest {
+			longest = length
+		}
+		// Allow roughly one typo for every three characters
+		if existing != key && distance*3 <= longest+1 {
+			candidates = append(candidates, suggestion{key: existing, distance: distance})
+		}
+	}
+
+	// keys are sorted so a stable sort keeps ties in alphabetical order
+	sort.Stable(candidates)
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	var keysFound []string
+	for _, candidate := range candidates {
+		keysFound = append(keysFound, candidate.key)
+	}
+	return keysFound
+}
+
+// editDistance will return the number of insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn a into b
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	rows := make([][]int, len(source)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(target)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			rows[i][j] = minInt(rows[i-1][j]+1, minInt(rows[i][j-1]+1, rows[i-1][j-1]+cost))
+			if i > 1 && j > 1 && source[i-1] == target[j-2] && source[i-2] == target[j-1] {
+				rows[i][j] = minInt(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(source)][len(target)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
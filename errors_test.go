@@ -0,0 +1,101 @@
+//go:build go1.13
+// +build go1.13
+
+package forge_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+primary {
+  name = "primary";
+  hosts = ["a", "b"];
+}
+dangling = primary.missing;
+first = .second;
+second = .first;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = settings.Resolve("primary.sub.key")
+	if errors.Is(err, forge.ErrNotExists) == false {
+		t.Errorf("expected ErrNotExists got %v", err)
+	}
+	var notFound *forge.NotFoundError
+	if errors.As(err, &notFound) == false || notFound.Missing != "primary.sub" {
+		t.Errorf("expected a *NotFoundError missing 'primary.sub' got %v", err)
+	}
+
+	_, err = settings.ResolveList("primary.name")
+	var typeErr *forge.TypeError
+	if errors.As(err, &typeErr) == false || typeErr.Path != "primary.name" {
+		t.Errorf("expected a *TypeError for 'primary.name' got %v", err)
+	}
+	if errors.Is(err, forge.ErrTypeMismatch) == false {
+		t.Errorf("expected ErrTypeMismatch got %v", err)
+	}
+
+	_, err = settings.Resolve("primary.hosts[5]")
+	var indexErr *forge.IndexError
+	if errors.As(err, &indexErr) == false || indexErr.Path != "primary.hosts" || indexErr.Index != 5 || indexErr.Length != 2 {
+		t.Errorf("expected an *IndexError for 'primary.hosts' got %v", err)
+	}
+	if errors.Is(err, forge.ErrIndexOutOfRange) == false {
+		t.Errorf("expected ErrIndexOutOfRange got %v", err)
+	}
+
+	_, err = settings.GetString("dangling")
+	if errors.Is(err, forge.ErrDanglingReference) == false || errors.Is(err, forge.ErrNotExists) == false {
+		t.Errorf("expected ErrDanglingReference wrapping ErrNotExists got %v", err)
+	}
+	if errors.Is(err, forge.ErrReferenceCycle) {
+		t.Errorf("expected a dangling reference not to be a cycle got %v", err)
+	}
+
+	_, err = settings.GetString("first")
+	var refErr *forge.ReferenceError
+	if errors.As(err, &refErr) == false || len(refErr.Cycle) == 0 {
+		t.Errorf("expected a *ReferenceError with a cycle got %v", err)
+	}
+	if errors.Is(err, forge.ErrReferenceCycle) == false {
+		t.Errorf("expected ErrReferenceCycle got %v", err)
+	}
+
+	source, err := forge.ParseString("primary = \"value\";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = settings.Merge(source)
+	var mergeErr *forge.MergeConflictError
+	if errors.As(err, &mergeErr) == false || mergeErr.Path != "primary" || mergeErr.Source != forge.STRING || mergeErr.Target != forge.SECTION {
+		t.Errorf("expected a *MergeConflictError for 'primary' got %v", err)
+	}
+	if errors.Is(err, forge.ErrMergeConflict) == false {
+		t.Errorf("expected ErrMergeConflict got %v", err)
+	}
+
+	// Errors with added context still match
+	listSettings, err := forge.ParseString("hosts = [missing];")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = listSettings.GetStringSlice("hosts")
+	if errors.Is(err, forge.ErrDanglingReference) == false {
+		t.Errorf("expected ErrDanglingReference got %v", err)
+	}
+
+	settings.Freeze()
+	err = settings.SetString("primary", "value")
+	if errors.Is(err, forge.ErrFrozen) == false {
+		t.Errorf("expected ErrFrozen got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package forge
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// BindFlags will register a flag on flags for every value in section which is not a Section, named
+// after its path (e.g. -db.port for the key port in the Section db). Flags are typed from the ValueType
+// of the value and default to the current value. Lists are set with the syntax of a config value
+// (e.g. -hosts='["a", "b"]') and References are typed from the value they refer to, References to
+// a Section are skipped since the values of that Section have flags of their own.
+//
+// When a flag is set (e.g. by `flag.FlagSet.Parse`) the new value is written back to section with
+// `Section.SetBoolean`, `Section.SetInteger`, `Section.SetFloat`, `Section.SetString` or `Section.Set`.
+// Will respond with an error if a flag with the same name is already registered
+func BindFlags(flags *flag.FlagSet, section *Section) error {
+	return bindFlags(flags, section, nil)
+}
+
+func bindFlags(flags *flag.FlagSet, section *Section, path []pathSegment) error {
+	for _, key := range section.OrderedKeys() {
+		keyPath := appendPath(path, pathSegment{key: key})
+		value, err := dereference(section.values[key])
+		if err != nil {
+			// A Reference which cannot be resolved has no type to bind a flag for
+			continue
+		}
+
+		if child, ok := value.(*Section); ok {
+			// DEV: Only recurse into child Sections, a Reference may refer to a Section containing it
+			if section.values[key] != value {
+				continue
+			}
+			if err = bindFlags(flags, child, keyPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := formatPath(keyPath)
+		if flags.Lookup(name) != nil {
+			return fmt.Errorf("flag -%s is already registered", name)
+		}
+		usage := fmt.Sprintf("set %s (%s)", name, value.GetType())
+		flags.Var(&sectionFlag{section: section, key: key, valueType: value.GetType()}, name, usage)
+	}
+	return nil
+}
+
+// PopulateFromFlags will set a value in section for every flag of flags, the reverse of `BindFlags`.
+// Flag names are paths (e.g. -db.port sets port in the Section db), missing Sections are created and
+// values keep the type of the flag when possible. Every flag is used, including flags which were not set
+func PopulateFromFlags(flags *flag.FlagSet, section *Section) error {
+	values, err := flagsSection(flags, true)
+	if err != nil {
+		return err
+	}
+	return section.Merge(values)
+}
+
+// sectionFlag is a flag.Value which reads and writes a value of a Section
+type sectionFlag struct {
+	section   *Section
+	key       string
+	valueType ValueType
+}
+
+func (f *sectionFlag) String() string {
+	// DEV: The flag package calls String on a zero sectionFlag to check for a zero default value
+	if f.section == nil {
+		return ""
+	}
+	value, err := f.section.getDereferenced(f.key)
+	if err != nil {
+		return ""
+	}
+	if list, ok := value.(*List); ok {
+		encoded, err := EncodeJSON(list)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+	converted, err := toString(value, false)
+	if err != nil {
+		return ""
+	}
+	return converted
+}
+
+// Get will return the current value, a Go value for primative values or a *List
+func (f *sectionFlag) Get() interface{} {
+	value, err := f.section.getDereferenced(f.key)
+	if err != nil {
+		return nil
+	}
+	if list, ok := value.(*List); ok {
+		return list
+	}
+	return value.GetValue()
+}
+
+// IsBoolFlag will return true for BOOLEAN values so they can be set without a value (e.g. -debug)
+func (f *sectionFlag) IsBoolFlag() bool {
+	return f.valueType == BOOLEAN
+}
+
+// Set will parse str as the type of the value and write it back to the Section
+func (f *sectionFlag) Set(str string) error {
+	var value interface{}
+	var err error
+	switch f.valueType {
+	case BOOLEAN:
+		value, err = strconv.ParseBool(str)
+	case FLOAT:
+		value, err = strconv.ParseFloat(str, 64)
+	case INTEGER:
+		value, err = strconv.ParseInt(str, 10, 64)
+	case LIST:
+		var parsed Value
+		parsed, err = parseValue(str)
+		if err == nil && parsed.GetType() != LIST {
+			err = &TypeError{Want: LIST, Got: parsed.GetType()}
+		}
+		value = parsed
+	default:
+		value = str
+	}
+	if err != nil {
+		return err
+	}
+
+	// References cannot be updated, so they are replaced by the new value
+	if _, ok := f.section.values[f.key].(*Reference); ok {
+		if list, ok := value.(*List); ok {
+			return f.section.Set(f.key, list)
+		}
+		primative, err := NewPrimative(value)
+		if err != nil {
+			return err
+		}
+		return f.section.Set(f.key, primative)
+	}
+
+	switch value := value.(type) {
+	case bool:
+		return f.section.SetBoolean(f.key, value)
+	case float64:
+		return f.section.SetFloat(f.key, value)
+	case int64:
+		return f.section.SetInteger(f.key, value)
+	case string:
+		return f.section.SetString(f.key, value)
+	case *List:
+		return f.section.Set(f.key, value)
+	}
+	return errors.New("unsupported flag value")
+}
+
+// flagsSection will create a Section from the flags in flags, only the flags which were set
+// unless all is true, see `Layers.AddFlags` and `PopulateFromFlags`
+func flagsSection(flags *flag.FlagSet, all bool) (*Section, error) {
+	section := NewSection()
+
+	var err error
+	visit := func(f *flag.Flag) {
+		if err == nil {
+			if setErr := section.setKeys(SplitPath(f.Name), flagValue(f)); setErr != nil {
+				err = wrapError(setErr, "flag -%s", f.Name)
+			}
+		}
+	}
+	if all {
+		flags.VisitAll(visit)
+	} else {
+		flags.Visit(visit)
+	}
+	return section, err
+}
+
+// flagValue will convert the value of f into a Value, keeping its type when possible
+func flagValue(f *flag.Flag) Value {
+	getter, ok := f.Value.(flag.Getter)
+	if ok == false {
+		return NewString(f.Value.String())
+	}
+
+	switch value := getter.Get().(type) {
+	case bool:
+		return NewBoolean(value)
+	case float64:
+		return NewFloat(value)
+	case int:
+		return NewInteger(int64(value))
+	case int64:
+		return NewInteger(value)
+	case uint:
+		if uint64(value) <= uint64(1<<63-1) {
+			return NewInteger(int64(value))
+		}
+	case uint64:
+		if value <= uint64(1<<63-1) {
+			return NewInteger(int64(value))
+		}
+	case nil:
+		return NewNull()
+	case string:
+		return NewString(value)
+	case time.Duration:
+		return NewString(value.String())
+	case Value:
+		return value.Clone()
+	}
+	return NewString(f.Value.String())
+}
@@ -0,0 +1,145 @@
+package forge_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestBindFlags(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+name = "app";
+debug = false;
+db {
+  host = "localhost";
+  port = 5432;
+  timeout = 1.5;
+  replicas = ["a"];
+}
+primary_host = db.host;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	if err = forge.BindFlags(flags, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	port := flags.Lookup("db.port")
+	if port == nil || port.DefValue != "5432" {
+		t.Fatalf("expected a -db.port flag defaulting to 5432 got %v", port)
+	}
+	if getter, ok := port.Value.(flag.Getter); ok == false || getter.Get() != int64(5432) {
+		t.Errorf("expected -db.port to get int64 5432")
+	}
+
+	err = flags.Parse([]string{
+		"-db.port", "6543", "-debug", "-db.timeout=2.5", `-db.replicas=["b", "c"]`, "-primary_host", "remote",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":         "app",
+		"debug":        true,
+		"db.host":      "localhost",
+		"db.port":      int64(6543),
+		"db.timeout":   2.5,
+		"primary_host": "remote",
+	}
+	for path, want := range expected {
+		value, err := settings.Resolve(path)
+		if err != nil {
+			t.Errorf("%s: %s", path, err)
+			continue
+		}
+		if value.GetValue() != want {
+			t.Errorf("expected %s to be %#v got %#v", path, want, value.GetValue())
+		}
+	}
+	replicas, err := settings.ResolveList("db.replicas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strs, err := replicas.Strings()
+	if err != nil || len(strs) != 2 || strs[0] != "b" || strs[1] != "c" {
+		t.Errorf("expected [b c] got %v (%v)", strs, err)
+	}
+
+	if err = flags.Parse([]string{"-db.port", "not a number"}); err == nil {
+		t.Error("expected an error setting an integer flag to a string")
+	}
+	if err = forge.BindFlags(flags, settings); err == nil {
+		t.Error("expected an error binding flags which are already registered")
+	}
+}
+
+func TestBindFlagsSectionReferences(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+a {
+  x = 1;
+  self = a;
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	if err = forge.BindFlags(flags, settings); err != nil {
+		t.Fatal(err)
+	}
+	if flags.Lookup("a.x") == nil {
+		t.Error("expected a -a.x flag")
+	}
+	if flags.Lookup("a.self") != nil || flags.Lookup("a.self.x") != nil {
+		t.Error("expected no flags for a reference to a section")
+	}
+}
+
+func TestPopulateFromFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.String("db.host", "localhost", "database host")
+	flags.Int("db.port", 5432, "database port")
+	flags.Bool("debug", false, "debug mode")
+	if err := flags.Parse([]string{"-db.port", "6543"}); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := forge.ParseString("name = \"app\";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = forge.PopulateFromFlags(flags, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":    "app",
+		"db.host": "localhost",
+		"db.port": int64(6543),
+		"debug":   false,
+	}
+	for path, want := range expected {
+		value, err := settings.Resolve(path)
+		if err != nil {
+			t.Errorf("%s: %s", path, err)
+			continue
+		}
+		if value.GetValue() != want {
+			t.Errorf("expected %s to be %#v got %#v", path, want, value.GetValue())
+		}
+	}
+}
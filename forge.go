@@ -32,7 +32,7 @@
 //
 // Config file format:
 //
-//     IDENTIFIER: [_a-zA-Z]([_a-zA-Z0-9]+)?
+//     IDENTIFIER: [_a-zA-Z]([_a-zA-Z0-9-]+)?
 //     NUMBERS: [0-9]+
 //     END: ';' | '\n'
 //
@@ -41,13 +41,16 @@
 //     INTEGER: ('-')? NUMBERS
 //     FLOAT: ('-')? NUMBERS '.' NUMBERS
 //     STRING: ['"] .* ['"]
-//     REFERENCE: (IDENTIFIER)? ('.' IDENTIFIER)+
+//     KEY: IDENTIFIER | STRING
+//     INDEX: '[' NUMBERS ']'
+//     REFERENCE: ('.'*)? (KEY)? ('.' KEY INDEX*)+ | KEY INDEX+ ('.' KEY INDEX*)*
 //     VALUE: BOOL | NULL | INTEGER | FLOAT | STRING | REFERENCE
 //     LIST: '[' (VALUE | LIST) (',' NEWLINE* (VALUE | LIST))+ ']'
 //
 //     INCLUDE: 'include ' STRING END
-//     DIRECTIVE: (IDENTIFIER '=' (VALUE | LIST) | INCLUDE) END
-//     SECTION: IDENTIFIER '{' (DIRECTIVE | SECTION)* '}'
+//     KEY_PATH: KEY ('.' KEY)*
+//     DIRECTIVE: (KEY_PATH '=' (VALUE | LIST) | INCLUDE) END
+//     SECTION: KEY_PATH '{' (DIRECTIVE | SECTION)* '}'
 //     COMMENT: '#' .* '\n'
 //
 //     CONFIG_FILE: (COMMENT | DIRECTIVE | SECTION)*
@@ -74,12 +77,34 @@
 //  * Local reference:
 //      An identifier which main contain periods which starts with a period, the references
 //      are resolved from the settings current section (e.g. .value, .sub_section.value)
+//  * Parent reference:
+//      A local reference which starts with more than one period, every period after the first steps up to the
+//      parent of the settings current section (e.g. ..sibling.value, ...value)
+//  * Index reference:
+//      Any reference can access an element of a list by index (e.g. servers[0], .servers[0].host, matrix[1][0]).
+//      References may be used anywhere a value is allowed, including inside lists (e.g. [primary.host, .host]).
+//  References are resolved when their value is requested, a reference which cannot be resolved or which is part
+//  of a reference cycle has a null value. Use `Section.ValidateReferences` after parsing to find every dangling
+//  reference and reference cycle, or `Reference.Resolve` to get the error for a single reference.
+//  The typed getters (e.g. `Section.GetString` or `List.GetInteger`) follow references, including chains of
+//  references, and respond with a *ReferenceError when a reference cannot be resolved.
+//
+// Keys
+//  Setting and section names are identifiers, which may also contain hyphens and unicode letters
+//  (e.g. content-length), or quoted strings (e.g. "content-type", "10.0.0.1", "include").
+//  Quoted keys can be used in references (e.g. "10.0.0.1".port, .sub_section."a.b").
+//  When resolving a key containing a period with `Section.Resolve` the period must be escaped with
+//  a backslash (e.g. `10\.0\.0\.1.port`), see `EscapeKey` and `JoinPath`.
 //
 // Directives
 //  * Comment:
 //      A comment is a pound symbol ('#') followed by any text any which ends with a newline (e.g. '# I am a comment\n')
 //      A comment can either be on a line of it's own or at the end of any line. Nothing can come after the comment
 //      until after the newline.
+//      Comments on the lines directly before a directive or section are its leading comments, a blank line
+//      ends them, and a comment at the end of the line a directive or section is defined on is its trailing
+//      comment, these are available from `Section.GetKeyComments`. A comment after the closing brace of a
+//      section is its trailing comment when there is no comment after the opening brace.
 //  * Directive:
 //      A directive is a setting, a identifier and a value. They are in the format '<identifier> = <value>;'
 //      All directives must end in either a semicolon or newline. The value can be any of the types defined above.
@@ -87,6 +112,10 @@
 //      A section is a grouping of directives under a common name. They are in the format '<section_name> { <directives> }'.
 //      All sections must be wrapped in braces ('{', '}') and must all have a name. They do not end in a semicolon.
 //      Sections may be left empty, they do not have to contain any directives.
+//  * Dotted keys:
+//      Directives and sections may be defined with a dotted key (e.g. 'db.primary.host = "x";' or 'db.primary { }')
+//      which will define the last key in the nested sections, creating any sections which do not exist yet.
+//      It is an error to define a dotted key through an existing value which is not a section.
 //  * Include:
 //      An include statement tells the config parser to include the contents of another config file where the include
 //      statement is defined. Includes are in the format 'include "<pattern>";'. The <pattern> can be any glob
@@ -147,3 +147,294 @@ func TestParseFile(t *testing.T) {
 	values := settings.ToMap()
 	assertDirectives(values, t)
 }
+
+func TestParseKeyComments(t *testing.T) {
+	settings, err := forge.ParseString(`
+# Leading global comment
+# Spans multiple lines
+global = "value"  # Trailing global comment
+no_comments = true;
+
+# Leading section comment
+section { # Trailing section open comment
+  # Leading nested comment
+  nested = .other;  # Trailing nested comment
+  other = 1
+} # Trailing section close comment
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments, err := settings.GetKeyComments("global")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(len(comments.Leading), 2, t)
+	assertEqual(comments.Leading[0], " Leading global comment", t)
+	assertEqual(comments.Leading[1], " Spans multiple lines", t)
+	assertEqual(comments.Trailing, " Trailing global comment", t)
+
+	comments, err = settings.GetKeyComments("no_comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(len(comments.Leading), 0, t)
+	assertEqual(comments.Trailing, "", t)
+
+	comments, err = settings.GetKeyComments("section")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(len(comments.Leading), 1, t)
+	assertEqual(comments.Leading[0], " Leading section comment", t)
+	assertEqual(comments.Trailing, " Trailing section open comment", t)
+
+	section, err := settings.GetSection("section")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments, err = section.GetKeyComments("nested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(len(comments.Leading), 1, t)
+	assertEqual(comments.Leading[0], " Leading nested comment", t)
+	assertEqual(comments.Trailing, " Trailing nested comment", t)
+
+	_, err = settings.GetKeyComments("missing")
+	if _, ok := err.(*forge.NotFoundError); ok == false {
+		t.Errorf("expected a *NotFoundError got %v", err)
+	}
+}
+
+func TestParseKeyCommentsBlankLine(t *testing.T) {
+	settings, err := forge.ParseString(`# File header
+
+# Leading comment
+key = 1
+section {
+  other = 2
+} # Trailing section close comment
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments, err := settings.GetKeyComments("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(len(comments.Leading), 1, t)
+	assertEqual(comments.Leading[0], " Leading comment", t)
+
+	// Without a comment after the opening brace the closing brace comment documents the section
+	comments, err = settings.GetKeyComments("section")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(comments.Trailing, " Trailing section close comment", t)
+}
+
+func TestParseQuotedKeys(t *testing.T) {
+	settings, err := forge.ParseString(`
+"content-type" = "json"
+true = "keyword key"
+"include" = "not an include"
+content-length = 50
+größe = 10
+"10.0.0.1" {
+  port = 8080
+}
+port_ref = "10.0.0.1".port
+local {
+  "a.b" = "dotted"
+  ref = ."a.b"
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := settings.ToMap()
+	assertEqual(values["content-type"], "json", t)
+	assertEqual(values["true"], "keyword key", t)
+	assertEqual(values["include"], "not an include", t)
+	assertEqual(values["content-length"], int64(50), t)
+	assertEqual(values["größe"], int64(10), t)
+	assertEqual(values["port_ref"], int64(8080), t)
+
+	value, err := settings.Resolve("10\\.0\\.0\\.1.port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(value.GetValue(), int64(8080), t)
+
+	value, err = settings.Resolve(forge.JoinPath("local", "ref"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(value.GetValue(), "dotted", t)
+}
+
+func TestParseKeyMissingAssignment(t *testing.T) {
+	_, err := forge.ParseString(`key "value"`)
+	if err == nil {
+		t.Fatal("expected a syntax error for a key without '=' or '{'")
+	}
+}
+
+func TestParseDottedKeys(t *testing.T) {
+	settings, err := forge.ParseString(`
+db {
+  name = "app"
+}
+db.primary.host = "primary.local"
+db.primary.port = 5432
+db.replica {
+  host = "replica.local"
+}
+section {
+  nested.key = true
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := settings.ToMap()
+	db := values["db"].(map[string]interface{})
+	assertEqual(db["name"], "app", t)
+	primary := db["primary"].(map[string]interface{})
+	assertEqual(primary["host"], "primary.local", t)
+	assertEqual(primary["port"], int64(5432), t)
+	replica := db["replica"].(map[string]interface{})
+	assertEqual(replica["host"], "replica.local", t)
+	section := values["section"].(map[string]interface{})
+	nested := section["nested"].(map[string]interface{})
+	assertEqual(nested["key"], true, t)
+}
+
+func TestParseDottedKeyThroughValue(t *testing.T) {
+	_, err := forge.ParseString(`
+db = "not a section"
+db.host = "x"
+`)
+	if err == nil {
+		t.Fatal("expected an error assigning through a non-section value")
+	}
+	assertEqual(err.Error(), "syntax error line <2> column <8>: cannot assign 'db.host', 'db' is a STRING not a SECTION", t)
+}
+
+func TestParseDottedSectionExtends(t *testing.T) {
+	settings, err := forge.ParseString(`
+db.primary.host = "primary.local"
+db.primary {
+  port = 5432
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := settings.ToMap()
+	primary := values["db"].(map[string]interface{})["primary"].(map[string]interface{})
+	assertEqual(primary["host"], "primary.local", t)
+	assertEqual(primary["port"], int64(5432), t)
+
+	_, err = forge.ParseString(`
+db.primary = "not a section"
+db.primary {
+  port = 5432
+}
+`)
+	if err == nil {
+		t.Fatal("expected an error defining a section over a non-section value")
+	}
+	assertEqual(err.Error(), "syntax error line <2> column <11>: cannot assign 'db.primary', 'db.primary' is a STRING not a SECTION", t)
+}
+
+func TestParseReferencesInLists(t *testing.T) {
+	settings, err := forge.ParseString(`
+primary { host = "primary.local" }
+secondary { host = "secondary.local" }
+hosts = [primary.host, secondary.host]
+servers = [
+  "a.local",
+  .hosts[1],
+]
+nested = [[1, 2], [3, 4]]
+first = servers[0]
+second_nested = nested[1][0]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := settings.ToMap()
+	hosts := values["hosts"].([]interface{})
+	assertEqual(len(hosts), 2, t)
+	assertEqual(hosts[0], "primary.local", t)
+	assertEqual(hosts[1], "secondary.local", t)
+	servers := values["servers"].([]interface{})
+	assertEqual(servers[1], "secondary.local", t)
+	assertEqual(values["first"], "a.local", t)
+	assertEqual(values["second_nested"], int64(3), t)
+
+	value, err := settings.Resolve("hosts[0]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(value.GetValue(), "primary.local", t)
+
+	_, err = settings.Resolve("hosts[2]")
+	if err == nil {
+		t.Fatal("expected an error resolving an index out of range")
+	}
+	_, err = settings.Resolve("primary[0]")
+	if err == nil {
+		t.Fatal("expected an error resolving an index of a section")
+	}
+}
+
+func TestParseParentReferences(t *testing.T) {
+	settings, err := forge.ParseString(`
+name = "root"
+app {
+  name = "app"
+  db {
+    host = "db.local"
+  }
+  cache {
+    db_host = ..db.host
+    app_name = ..name
+    root_name = ...name
+  }
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := settings.ToMap()
+	app := values["app"].(map[string]interface{})
+	cache := app["cache"].(map[string]interface{})
+	assertEqual(cache["db_host"], "db.local", t)
+	assertEqual(cache["app_name"], "app", t)
+	assertEqual(cache["root_name"], "root", t)
+
+	value, err := settings.Resolve("app.cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err = value.(*forge.Section).Resolve("..db.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(value.GetValue(), "db.local", t)
+
+	_, err = settings.Resolve("..name")
+	if err == nil {
+		t.Fatal("expected an error resolving the parent of the root section")
+	}
+}
@@ -0,0 +1,174 @@
+//go:build go1.18
+// +build go1.18
+
+package forge
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	listType     = reflect.TypeOf((*List)(nil))
+	sectionType  = reflect.TypeOf((*Section)(nil))
+	valueType    = reflect.TypeOf((*Value)(nil)).Elem()
+)
+
+// Get will resolve the value at path (e.g. "http.timeout") and convert it to the type T.
+// References are followed and primative values are converted with the `Primative.As*` methods.
+//
+// Supported types are string, bool, every integer and float type, time.Duration (parsed from
+// a string like "1m30s" or taken from an integer as nanoseconds), *List, *Section and Value,
+// slices of any supported type converted from a List (e.g. []string) and maps with string keys
+// of any supported type converted from a Section (e.g. map[string]int)
+//
+//	timeout, err := forge.Get[time.Duration](settings, "http.timeout")
+//	hosts, err := forge.Get[[]string](settings, "hosts")
+func Get[T any](section *Section, path string) (T, error) {
+	var result T
+	value, err := section.Resolve(path)
+	if err != nil {
+		return result, err
+	}
+
+	converted, err := convertValue(value, reflect.TypeOf(&result).Elem(), section.strictTypes())
+	if _, ok := err.(*TypeError); ok {
+		return result, section.withPath(err, path)
+	} else if err != nil {
+		return result, wrapError(err, "could not convert value at '%s'", path)
+	}
+	return converted.Interface().(T), nil
+}
+
+// GetOr will resolve the value at path like `Get`, responding with fallback instead
+// when the value does not exist or cannot be converted to the type T
+//
+//	workers := forge.GetOr[int](settings, "workers", 4)
+func GetOr[T any](section *Section, path string, fallback T) T {
+	value, err := Get[T](section, path)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func convertValue(value Value, target reflect.Type, strict bool) (reflect.Value, error) {
+	value, err := dereference(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	switch target {
+	case durationType:
+		return convertDuration(value, strict)
+	case listType:
+		list, err := toList(value)
+		return reflect.ValueOf(list), err
+	case sectionType:
+		section, err := toSection(value)
+		return reflect.ValueOf(section), err
+	case valueType:
+		converted := reflect.New(valueType).Elem()
+		converted.Set(reflect.ValueOf(value))
+		return converted, nil
+	}
+
+	converted := reflect.New(target).Elem()
+	switch target.Kind() {
+	case reflect.String:
+		str, err := toString(value, strict)
+		if err != nil {
+			return converted, err
+		}
+		converted.SetString(str)
+	case reflect.Bool:
+		boolean, err := toBoolean(value, strict)
+		if err != nil {
+			return converted, err
+		}
+		converted.SetBool(boolean)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, err := toInteger(value, strict)
+		if err != nil {
+			return converted, err
+		}
+		if converted.OverflowInt(integer) {
+			return converted, fmt.Errorf("value %d overflows %s", integer, target)
+		}
+		converted.SetInt(integer)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		integer, err := toInteger(value, strict)
+		if err != nil {
+			return converted, err
+		}
+		if integer < 0 || converted.OverflowUint(uint64(integer)) {
+			return converted, fmt.Errorf("value %d overflows %s", integer, target)
+		}
+		converted.SetUint(uint64(integer))
+	case reflect.Float32, reflect.Float64:
+		float, err := toFloat(value, strict)
+		if err != nil {
+			return converted, err
+		}
+		if converted.OverflowFloat(float) {
+			return converted, fmt.Errorf("value %f overflows %s", float, target)
+		}
+		converted.SetFloat(float)
+	case reflect.Slice:
+		list, err := toList(value)
+		if err != nil {
+			return converted, err
+		}
+		converted = reflect.MakeSlice(target, list.Length(), list.Length())
+		for i, element := range list.GetValues() {
+			convertedElement, err := convertValue(element, target.Elem(), strict)
+			if err != nil {
+				return converted, listElementError(err, i)
+			}
+			converted.Index(i).Set(convertedElement)
+		}
+	case reflect.Map:
+		if target.Key().Kind() != reflect.String {
+			return converted, fmt.Errorf("unsupported map key type %s, must be a string", target.Key())
+		}
+		section, err := toSection(value)
+		if err != nil {
+			return converted, err
+		}
+		converted = reflect.MakeMap(target)
+		for _, key := range section.Keys() {
+			element, _ := section.Get(key)
+			convertedElement, err := convertValue(element, target.Elem(), strict)
+			if _, ok := err.(*TypeError); ok {
+				return converted, prefixErrorPath(err, "."+EscapeKey(key))
+			} else if err != nil {
+				return converted, wrapError(err, "key '%s'", key)
+			}
+			converted.SetMapIndex(reflect.ValueOf(key).Convert(target.Key()), convertedElement)
+		}
+	default:
+		return converted, fmt.Errorf("unsupported type %s", target)
+	}
+	return converted, nil
+}
+
+func convertDuration(value Value, strict bool) (reflect.Value, error) {
+	var duration time.Duration
+	switch value.GetType() {
+	case STRING:
+		str, _ := toString(value, strict)
+		parsed, err := time.ParseDuration(str)
+		if err != nil {
+			return reflect.ValueOf(duration), err
+		}
+		duration = parsed
+	case INTEGER:
+		integer, _ := toInteger(value, strict)
+		duration = time.Duration(integer)
+	default:
+		return reflect.ValueOf(duration), fmt.Errorf("could not convert %s value to duration", value.GetType())
+	}
+	return reflect.ValueOf(duration), nil
+}
@@ -0,0 +1,129 @@
+//go:build go1.18
+// +build go1.18
+
+package forge_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+http {
+  timeout = "1m30s"
+  retries = 3
+  ratio = 0.25
+  enabled = true
+}
+hosts = ["a.local", "b.local"]
+ports = [80, 443]
+matrix = [[1, 2], [3]]
+limits { low = 1; high = 10 }
+host_reference = hosts[0]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout, err := forge.Get[time.Duration](settings, "http.timeout")
+	if err != nil || timeout != 90*time.Second {
+		t.Errorf("expected 1m30s got %v (%v)", timeout, err)
+	}
+	retries, err := forge.Get[int](settings, "http.retries")
+	if err != nil || retries != 3 {
+		t.Errorf("expected 3 got %v (%v)", retries, err)
+	}
+	ratio, err := forge.Get[float32](settings, "http.ratio")
+	if err != nil || ratio != 0.25 {
+		t.Errorf("expected 0.25 got %v (%v)", ratio, err)
+	}
+	enabled, err := forge.Get[bool](settings, "http.enabled")
+	if err != nil || enabled != true {
+		t.Errorf("expected true got %v (%v)", enabled, err)
+	}
+	host, err := forge.Get[string](settings, "host_reference")
+	if err != nil || host != "a.local" {
+		t.Errorf("expected 'a.local' got %v (%v)", host, err)
+	}
+
+	hosts, err := forge.Get[[]string](settings, "hosts")
+	if err != nil || len(hosts) != 2 || hosts[0] != "a.local" || hosts[1] != "b.local" {
+		t.Errorf("expected [a.local b.local] got %v (%v)", hosts, err)
+	}
+	ports, err := forge.Get[[]uint16](settings, "ports")
+	if err != nil || len(ports) != 2 || ports[1] != 443 {
+		t.Errorf("expected [80 443] got %v (%v)", ports, err)
+	}
+	matrix, err := forge.Get[[][]int](settings, "matrix")
+	if err != nil || len(matrix) != 2 || matrix[0][1] != 2 || matrix[1][0] != 3 {
+		t.Errorf("expected [[1 2] [3]] got %v (%v)", matrix, err)
+	}
+	limits, err := forge.Get[map[string]int64](settings, "limits")
+	if err != nil || len(limits) != 2 || limits["low"] != 1 || limits["high"] != 10 {
+		t.Errorf("expected map[high:10 low:1] got %v (%v)", limits, err)
+	}
+	section, err := forge.Get[*forge.Section](settings, "http")
+	if err != nil || section.Exists("timeout") == false {
+		t.Errorf("expected http section (%v)", err)
+	}
+
+	if _, err = forge.Get[int8](settings, "ports[1]"); err == nil {
+		t.Error("expected an overflow error")
+	}
+	if _, err = forge.Get[[]string](settings, "http"); err == nil {
+		t.Error("expected an error converting a section to a slice")
+	}
+	if _, err = forge.Get[string](settings, "missing"); err == nil {
+		t.Error("expected an error for a missing value")
+	}
+}
+
+func TestGetOr(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString("workers = 8\nname = 'app'\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if workers := forge.GetOr[int](settings, "workers", 4); workers != 8 {
+		t.Errorf("expected 8 got %d", workers)
+	}
+	if threads := forge.GetOr[int](settings, "threads", 4); threads != 4 {
+		t.Errorf("expected fallback 4 got %d", threads)
+	}
+	if name := forge.GetOr[int](settings, "name", 1); name != 1 {
+		t.Errorf("expected fallback 1 got %d", name)
+	}
+}
+
+func TestGetWrappedErrors(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+dangling = missing;
+hosts = [missing];
+ports { primary = missing; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = forge.Get[int](settings, "dangling"); errors.Is(err, forge.ErrDanglingReference) == false {
+		t.Errorf("expected ErrDanglingReference for Get[int] got %v", err)
+	}
+	if _, err = forge.Get[[]string](settings, "hosts"); errors.Is(err, forge.ErrDanglingReference) == false {
+		t.Errorf("expected ErrDanglingReference for Get[[]string] got %v", err)
+	}
+	_, err = forge.Get[map[string]int](settings, "ports")
+	var referenceErr *forge.ReferenceError
+	if errors.As(err, &referenceErr) == false || referenceErr.Name != "missing" {
+		t.Errorf("expected a *ReferenceError for Get[map[string]int] got %v", err)
+	}
+}
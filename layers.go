@@ -0,0 +1,139 @@
+package forge
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+// Layers combines an ordered list of sources of settings (e.g. defaults, files, environment
+// variables and flags) into one effective Section, each layer is merged on top of the layers
+// added before it with `Section.MergeWithOptions`
+//
+//	layers := forge.NewLayers()
+//	layers.AddDefaults(defaults)
+//	layers.AddFile("/etc/app/app.cfg")
+//	layers.AddEnv("APP", "_")  // APP_DB_HOST overrides db.host
+//	layers.AddFlags(flag.CommandLine)
+//	settings, err := layers.Load()
+type Layers struct {
+	// Options are used to merge every layer into the layers added before it
+	Options MergeOptions
+
+	layers []layer
+}
+
+// layer is a named source of settings which is loaded when the Layers are loaded
+type layer struct {
+	name string
+	load func() (*Section, error)
+}
+
+// NewLayers will create and initialize a new empty Layers
+func NewLayers() *Layers {
+	return &Layers{
+		layers: make([]layer, 0),
+	}
+}
+
+// AddLayer will add a layer with the provided name, load is called every time the Layers are loaded
+func (layers *Layers) AddLayer(name string, load func() (*Section, error)) {
+	layers.layers = append(layers.layers, layer{name: name, load: load})
+}
+
+// AddSection will add a layer with the provided name which supplies the settings of section
+func (layers *Layers) AddSection(name string, section *Section) {
+	layers.AddLayer(name, func() (*Section, error) {
+		return section, nil
+	})
+}
+
+// AddDefaults will add a layer named "defaults" which supplies the settings of defaults
+func (layers *Layers) AddDefaults(defaults *Section) {
+	layers.AddSection("defaults", defaults)
+}
+
+// AddFile will add a layer named after filename which supplies the settings parsed with `ParseFile`
+func (layers *Layers) AddFile(filename string) {
+	layers.AddLayer(filename, func() (*Section, error) {
+		return ParseFile(filename)
+	})
+}
+
+// AddEnv will add a layer named "env" which supplies a setting for every environment variable which
+// starts with prefix followed by separator. The rest of the name is split on separator into lowercase
+// keys (e.g. with the prefix "APP" and separator "_", APP_DB_HOST supplies db.host), see `Section.ApplyEnv`
+// for how the values are parsed
+func (layers *Layers) AddEnv(prefix string, separator string) {
+	layers.AddLayer("env", func() (*Section, error) {
+		overrides, _, err := envSection(os.Environ(), prefix, separator)
+		return overrides, err
+	})
+}
+
+// AddFlags will add a layer named "flags" which supplies a setting for every flag which was set on
+// the command line, flag names are paths (e.g. -db.host supplies db.host). The layer is read when the
+// Layers are loaded, so flags must be parsed before calling `Load`
+func (layers *Layers) AddFlags(flags *flag.FlagSet) {
+	layers.AddLayer("flags", func() (*Section, error) {
+		return flagsSection(flags, false)
+	})
+}
+
+// Load will load every layer and merge them in order into one effective Section
+func (layers *Layers) Load() (*Section, error) {
+	settings, _, err := layers.LoadWithOrigins()
+	return settings, err
+}
+
+// LoadWithOrigins will load every layer like `Load` and will also respond with the name of the
+// layer which supplied each value, keyed by the path of the value (e.g. "db.host"), Sections
+// are not included, only the values in them
+func (layers *Layers) LoadWithOrigins() (*Section, map[string]string, error) {
+	m, err := newMerger(layers.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := NewSection()
+	origins := make(map[string]string)
+	for _, layer := range layers.layers {
+		section, err := layer.load()
+		if err != nil {
+			return nil, nil, &LayerError{Layer: layer.name, Err: err}
+		}
+		if err = settings.MergeWithOptions(section, layers.Options); err != nil {
+			return nil, nil, &LayerError{Layer: layer.name, Err: err}
+		}
+		recordOrigins(section, nil, layer.name, origins, m.final)
+	}
+	return settings, origins, nil
+}
+
+// recordOrigins will record name as the origin of every value in section, except
+// for final values which already have an origin
+func recordOrigins(section *Section, path []pathSegment, name string, origins map[string]string, final map[string]bool) {
+	for _, key := range section.OrderedKeys() {
+		keyPath := appendPath(path, pathSegment{key: key})
+		formatted := formatPath(keyPath)
+		if final[formatted] && hasOrigin(origins, formatted) {
+			continue
+		}
+
+		if child, ok := section.values[key].(*Section); ok {
+			recordOrigins(child, keyPath, name, origins, final)
+			continue
+		}
+		origins[formatted] = name
+	}
+}
+
+// hasOrigin will return true if the value at path or any value below it has an origin
+func hasOrigin(origins map[string]string, path string) bool {
+	for recorded := range origins {
+		if recorded == path || strings.HasPrefix(recorded, path+".") {
+			return true
+		}
+	}
+	return false
+}
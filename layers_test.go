@@ -0,0 +1,129 @@
+package forge_test
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+// DEV: This test is not run in parallel since it sets environment variables
+func TestLayers(t *testing.T) {
+	defer setenv(t, "LAYERSAPP_DB_HOST", "env-host")()
+	defer setenv(t, "LAYERSAPP_LOG_LEVEL", "debug")()
+	defer setenv(t, "OTHERAPP_DB_HOST", "ignored")()
+
+	defaults, err := forge.ParseString(`
+db {
+  host = "localhost";
+  port = 5432;
+  user = "default";
+}
+log.level = "info";
+name = "default";
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "app.cfg")
+	writeConfig(t, filename, "db.user = \"file\";\ndb.host = \"file-host\";\n")
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.Int("db.port", 1, "database port")
+	flags.String("name", "flag-default", "application name")
+	if err = flags.Parse([]string{"-db.port", "6543"}); err != nil {
+		t.Fatal(err)
+	}
+
+	layers := forge.NewLayers()
+	layers.AddDefaults(defaults)
+	layers.AddFile(filename)
+	layers.AddEnv("LAYERSAPP", "_")
+	layers.AddFlags(flags)
+
+	settings, origins, err := layers.LoadWithOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]struct {
+		value  interface{}
+		origin string
+	}{
+		"db.host":   {"env-host", "env"},
+		"db.port":   {int64(6543), "flags"},
+		"db.user":   {"file", filename},
+		"log.level": {"debug", "env"},
+		"name":      {"default", "defaults"},
+	}
+	for path, want := range expected {
+		value, err := settings.Resolve(path)
+		if err != nil {
+			t.Errorf("%s: %s", path, err)
+			continue
+		}
+		if value.GetValue() != want.value {
+			t.Errorf("expected %s to be %v got %v", path, want.value, value.GetValue())
+		}
+		if origins[path] != want.origin {
+			t.Errorf("expected %s to come from '%s' got '%s'", path, want.origin, origins[path])
+		}
+	}
+	if len(origins) != len(expected) {
+		t.Errorf("expected %d origins got %v", len(expected), origins)
+	}
+
+	// The defaults are not changed by loading
+	user, err := defaults.ResolveString("db.user")
+	if err != nil || user != "default" {
+		t.Errorf("expected 'default' got %s (%v)", user, err)
+	}
+}
+
+func TestLayersErrors(t *testing.T) {
+	t.Parallel()
+
+	layers := forge.NewLayers()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	layers.AddFile(filepath.Join(dir, "missing.cfg"))
+	_, err := layers.Load()
+	if layerErr, ok := err.(*forge.LayerError); ok == false || layerErr.Err == nil {
+		t.Errorf("expected a *LayerError got %v", err)
+	}
+
+	defaults, err := forge.ParseString("db { host = \"localhost\"; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	override, err := forge.ParseString("db = \"not a section\";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers = forge.NewLayers()
+	layers.AddDefaults(defaults)
+	layers.AddSection("override", override)
+	_, err = layers.Load()
+	layerErr, ok := err.(*forge.LayerError)
+	if ok == false || layerErr.Layer != "override" {
+		t.Fatalf("expected a *LayerError for 'override' got %v", err)
+	}
+	if _, ok = layerErr.Err.(*forge.MergeConflictError); ok == false {
+		t.Errorf("expected a *MergeConflictError got %v", layerErr.Err)
+	}
+
+	// The merge options are used for every layer
+	layers.Options.TypeMismatch = forge.MismatchReplace
+	settings, err := layers.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := settings.GetString("db")
+	if err != nil || db != "not a section" {
+		t.Errorf("expected 'not a section' got %s (%v)", db, err)
+	}
+}
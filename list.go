@@ -7,6 +7,8 @@ import (
 
 // List struct used for holding data neede for Reference data type
 type List struct {
+	frozen bool
+	owner  *Section
 	values []Value
 }
 
@@ -32,17 +34,75 @@ func (list *List) GetValue() interface{} {
 	return values
 }
 
-// GetValues will return back the list of underlygin values
+// GetValues will return back the list of underlygin values,
+// a copy of them if the list is frozen
 func (list *List) GetValues() []Value {
+	if list.frozen {
+		return append(make([]Value, 0, len(list.values)), list.values...)
+	}
 	return list.values
 }
 
+// Clone will return a deep copy of this List and every value in it
+func (list *List) Clone() Value {
+	return newCloner().clone(list, nil)
+}
+
+// Equal will return true if other is a List of the same length where every value is Equal
+func (list *List) Equal(other Value) bool {
+	otherList, ok := other.(*List)
+	if ok == false || list.Length() != otherList.Length() {
+		return false
+	}
+	for i, value := range list.values {
+		if value.Equal(otherList.values[i]) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// strictTypes will return true if strict type conversion is enabled globally or for
+// the Section this List is stored in, see `Section.SetStrictTypes`
+func (list *List) strictTypes() bool {
+	if list.owner != nil {
+		return list.owner.strictTypes()
+	}
+	return StrictTypes()
+}
+
+// setOwner will record section as the Section this List and every List in it is stored in
+func (list *List) setOwner(section *Section) {
+	// DEV: A frozen List may be read concurrently, so it keeps its owner
+	if list.frozen {
+		return
+	}
+	list.owner = section
+	for _, value := range list.values {
+		if child, ok := value.(*List); ok {
+			child.setOwner(section)
+		}
+	}
+}
+
+// adopt will record the owner of this List as the owner of value if it is a List
+func (list *List) adopt(value Value) {
+	if child, ok := value.(*List); ok {
+		child.setOwner(list.owner)
+	}
+}
+
 // UpdateValue will set the underlying list value
 func (list *List) UpdateValue(value interface{}) error {
+	if list.frozen {
+		return &FrozenError{}
+	}
+
 	// Valid types
 	switch value.(type) {
 	case []Value:
 		list.values = value.([]Value)
+		list.setOwner(list.owner)
 	default:
 		msg := fmt.Sprintf("Unsupported type, %s must be of type []Value", value)
 		return errors.New(msg)
@@ -52,64 +112,76 @@ func (list *List) UpdateValue(value interface{}) error {
 
 // Get will return the Value at the index
 func (list *List) Get(idx int) (Value, error) {
-	if idx > list.Length() {
-		return nil, errors.New("index out of range")
+	if idx < 0 || idx >= list.Length() {
+		return nil, &IndexError{Index: idx, Length: list.Length()}
 	}
 	return list.values[idx], nil
 }
 
+// getDereferenced will get the value stored at the index, following it if it is a Reference
+func (list *List) getDereferenced(idx int) (Value, error) {
+	value, err := list.Get(idx)
+	if err != nil {
+		return nil, err
+	}
+	return dereference(value)
+}
+
 // GetBoolean will try to get the value stored at the index as a bool
 // will respond with an error if the value does not exist or cannot be converted to a bool
 func (list *List) GetBoolean(idx int) (bool, error) {
-	value, err := list.Get(idx)
+	value, err := list.getDereferenced(idx)
 	if err != nil {
 		return false, err
 	}
 
 	switch value.(type) {
 	case *Primative:
-		return value.(*Primative).AsBoolean()
+		converted, err := value.(*Primative).asBoolean(list.strictTypes())
+		return converted, prefixErrorPath(err, fmt.Sprintf("[%d]", idx))
 	}
 
-	return false, errors.New("could not convert unknown value to boolean")
+	return false, &TypeError{Path: fmt.Sprintf("[%d]", idx), Want: BOOLEAN, Got: value.GetType()}
 }
 
 // GetFloat will try to get the value stored at the index as a float64
 // will respond with an error if the value does not exist or cannot be converted to a float64
 func (list *List) GetFloat(idx int) (float64, error) {
-	value, err := list.Get(idx)
+	value, err := list.getDereferenced(idx)
 	if err != nil {
 		return float64(0), err
 	}
 
 	switch value.(type) {
 	case *Primative:
-		return value.(*Primative).AsFloat()
+		converted, err := value.(*Primative).asFloat(list.strictTypes())
+		return converted, prefixErrorPath(err, fmt.Sprintf("[%d]", idx))
 	}
 
-	return float64(0), errors.New("could not convert non-primative value to float")
+	return float64(0), &TypeError{Path: fmt.Sprintf("[%d]", idx), Want: FLOAT, Got: value.GetType()}
 }
 
 // GetInteger will try to get the value stored at the index as a int64
 // will respond with an error if the value does not exist or cannot be converted to a int64
 func (list *List) GetInteger(idx int) (int64, error) {
-	value, err := list.Get(idx)
+	value, err := list.getDereferenced(idx)
 	if err != nil {
 		return int64(0), err
 	}
 
 	switch value.(type) {
 	case *Primative:
-		return value.(*Primative).AsInteger()
+		converted, err := value.(*Primative).asInteger(list.strictTypes())
+		return converted, prefixErrorPath(err, fmt.Sprintf("[%d]", idx))
 	}
 
-	return int64(0), errors.New("could not convert non-primative value to integer")
+	return int64(0), &TypeError{Path: fmt.Sprintf("[%d]", idx), Want: INTEGER, Got: value.GetType()}
 }
 
 // GetList will try to get the value stored at the index as a List
 // will respond with an error if the value does not exist or is not a List
 func (list *List) GetList(idx int) (*List, error) {
-	value, err := list.Get(idx)
+	value, err := list.getDereferenced(idx)
 	if err != nil {
 		return nil, err
 	}
@@ -118,33 +190,222 @@ func (list *List) GetList(idx int) (*List, error) {
 		return value.(*List), nil
 	}
 
-	return nil, errors.New("could not fetch value as list")
+	return nil, &TypeError{Path: fmt.Sprintf("[%d]", idx), Want: LIST, Got: value.GetType()}
 }
 
 // GetString will try to get the value stored at the index as a string
 // will respond with an error if the value does not exist or cannot be converted to a string
 func (list *List) GetString(idx int) (string, error) {
-	value, err := list.Get(idx)
+	value, err := list.getDereferenced(idx)
 	if err != nil {
 		return "", err
 	}
 
 	switch value.(type) {
 	case *Primative:
-		return value.(*Primative).AsString()
+		converted, err := value.(*Primative).asString(list.strictTypes())
+		return converted, prefixErrorPath(err, fmt.Sprintf("[%d]", idx))
+	}
+
+	return "", &TypeError{Path: fmt.Sprintf("[%d]", idx), Want: STRING, Got: value.GetType()}
+}
+
+// elements will respond with every element of the List with References followed, when exact
+// is true every element must have the ValueType expected instead of being converted to it
+func (list *List) elements(expected ValueType, exact bool) ([]Value, error) {
+	values := make([]Value, len(list.values))
+	for i, value := range list.values {
+		value, err := dereference(value)
+		if err != nil {
+			return nil, listElementError(err, i)
+		}
+		if exact && value.GetType() != expected {
+			return nil, &TypeError{
+				Path: fmt.Sprintf("[%d]", i),
+				Want: expected,
+				Got:  value.GetType(),
+			}
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (list *List) booleans(exact bool, strict bool) ([]bool, error) {
+	values, err := list.elements(BOOLEAN, exact)
+	if err != nil {
+		return nil, err
+	}
+	booleans := make([]bool, len(values))
+	for i, value := range values {
+		if booleans[i], err = toBoolean(value, strict); err != nil {
+			return nil, listElementError(err, i)
+		}
+	}
+	return booleans, nil
+}
+
+// Booleans will convert every element of the List to a bool, see `Primative.AsBoolean`
+func (list *List) Booleans() ([]bool, error) {
+	return list.booleans(false, list.strictTypes())
+}
+
+// StrictBooleans will respond with every element of the List as a bool
+// will respond with an error if any element is not a BOOLEAN
+func (list *List) StrictBooleans() ([]bool, error) {
+	return list.booleans(true, true)
+}
+
+func (list *List) floats(exact bool, strict bool) ([]float64, error) {
+	values, err := list.elements(FLOAT, exact)
+	if err != nil {
+		return nil, err
 	}
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		if floats[i], err = toFloat(value, strict); err != nil {
+			return nil, listElementError(err, i)
+		}
+	}
+	return floats, nil
+}
+
+// Floats will convert every element of the List to a float64, see `Primative.AsFloat`
+func (list *List) Floats() ([]float64, error) {
+	return list.floats(false, list.strictTypes())
+}
 
-	return "", errors.New("could not convert non-primative value to string")
+// StrictFloats will respond with every element of the List as a float64
+// will respond with an error if any element is not a FLOAT
+func (list *List) StrictFloats() ([]float64, error) {
+	return list.floats(true, true)
+}
+
+func (list *List) integers(exact bool, strict bool) ([]int64, error) {
+	values, err := list.elements(INTEGER, exact)
+	if err != nil {
+		return nil, err
+	}
+	integers := make([]int64, len(values))
+	for i, value := range values {
+		if integers[i], err = toInteger(value, strict); err != nil {
+			return nil, listElementError(err, i)
+		}
+	}
+	return integers, nil
+}
+
+// Integers will convert every element of the List to a int64, see `Primative.AsInteger`
+func (list *List) Integers() ([]int64, error) {
+	return list.integers(false, list.strictTypes())
+}
+
+// StrictIntegers will respond with every element of the List as a int64
+// will respond with an error if any element is not an INTEGER
+func (list *List) StrictIntegers() ([]int64, error) {
+	return list.integers(true, true)
+}
+
+// Sections will respond with every element of the List as a Section
+// will respond with an error if any element is not a SECTION
+func (list *List) Sections() ([]*Section, error) {
+	values, err := list.elements(SECTION, true)
+	if err != nil {
+		return nil, err
+	}
+	sections := make([]*Section, len(values))
+	for i, value := range values {
+		sections[i] = value.(*Section)
+	}
+	return sections, nil
+}
+
+func (list *List) strings(exact bool, strict bool) ([]string, error) {
+	values, err := list.elements(STRING, exact)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(values))
+	for i, value := range values {
+		if strs[i], err = toString(value, strict); err != nil {
+			return nil, listElementError(err, i)
+		}
+	}
+	return strs, nil
+}
+
+// Strings will convert every element of the List to a string, see `Primative.AsString`
+func (list *List) Strings() ([]string, error) {
+	return list.strings(false, list.strictTypes())
+}
+
+// StrictStrings will respond with every element of the List as a string
+// will respond with an error if any element is not a STRING
+func (list *List) StrictStrings() ([]string, error) {
+	return list.strings(true, true)
+}
+
+// listElementError will add the index of the element which could not be converted to err
+func listElementError(err error, idx int) error {
+	if _, ok := err.(*TypeError); ok {
+		return prefixErrorPath(err, fmt.Sprintf("[%d]", idx))
+	}
+	return wrapError(err, "index %d", idx)
 }
 
 // Set will set the new Value at the index
-func (list *List) Set(idx int, value Value) {
+// will respond with an *IndexError if the index is out of range or a *FrozenError if the list is frozen
+func (list *List) Set(idx int, value Value) error {
+	if list.frozen {
+		return &FrozenError{}
+	}
+	if idx < 0 || idx >= list.Length() {
+		return &IndexError{Index: idx, Length: list.Length()}
+	}
+	list.values[idx] = value
+	list.adopt(value)
+	return nil
+}
+
+// Insert will insert the new Value at the index, shifting the Values at and after the index up by one.
+// An index equal to the length of the list appends the Value,
+// will respond with an *IndexError if the index is out of range or a *FrozenError if the list is frozen
+func (list *List) Insert(idx int, value Value) error {
+	if list.frozen {
+		return &FrozenError{}
+	}
+	if idx < 0 || idx > list.Length() {
+		return &IndexError{Index: idx, Length: list.Length()}
+	}
+	list.values = append(list.values, nil)
+	copy(list.values[idx+1:], list.values[idx:])
 	list.values[idx] = value
+	list.adopt(value)
+	return nil
+}
+
+// Remove will remove the Value at the index, shifting the Values after the index down by one
+// will respond with an *IndexError if the index is out of range or a *FrozenError if the list is frozen
+func (list *List) Remove(idx int) error {
+	if list.frozen {
+		return &FrozenError{}
+	}
+	if idx < 0 || idx >= list.Length() {
+		return &IndexError{Index: idx, Length: list.Length()}
+	}
+	list.values = append(list.values[:idx], list.values[idx+1:]...)
+	return nil
 }
 
 // Append will append a new Value on the end of the internal list
-func (list *List) Append(value Value) {
+// will respond with a *FrozenError if the list is frozen
+func (list *List) Append(value Value) error {
+	if list.frozen {
+		return &FrozenError{}
+	}
 	list.values = append(list.values, value)
+	list.adopt(value)
+	return nil
 }
 
 // Length will return back the total number of items in the list
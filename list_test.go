@@ -0,0 +1,99 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestListSet(t *testing.T) {
+	t.Parallel()
+
+	list := forge.NewList()
+	list.Append(forge.NewInteger(1))
+
+	if err := list.Set(0, forge.NewInteger(2)); err != nil {
+		t.Fatal(err)
+	}
+	value, err := list.GetInteger(0)
+	if err != nil || value != 2 {
+		t.Errorf("expected 2 got %d (%v)", value, err)
+	}
+
+	err = list.Set(1, forge.NewInteger(3))
+	if _, ok := err.(*forge.IndexError); ok == false {
+		t.Errorf("expected an *IndexError got %v", err)
+	}
+	if err = list.Set(-1, forge.NewInteger(3)); err == nil {
+		t.Error("expected an error setting a negative index")
+	}
+}
+
+func TestListInsertRemove(t *testing.T) {
+	t.Parallel()
+
+	list := forge.NewList()
+	list.Append(forge.NewString("b"))
+
+	if err := list.Insert(0, forge.NewString("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Insert(2, forge.NewString("d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Insert(2, forge.NewString("c")); err != nil {
+		t.Fatal(err)
+	}
+	strs, err := list.Strings()
+	if err != nil || len(strs) != 4 || strs[0] != "a" || strs[1] != "b" || strs[2] != "c" || strs[3] != "d" {
+		t.Errorf("expected [a b c d] got %v (%v)", strs, err)
+	}
+	if err = list.Insert(5, forge.NewString("f")); err == nil {
+		t.Error("expected an error inserting past the end of the list")
+	}
+
+	if err = list.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err = list.Remove(2); err != nil {
+		t.Fatal(err)
+	}
+	strs, err = list.Strings()
+	if err != nil || len(strs) != 2 || strs[0] != "a" || strs[1] != "c" {
+		t.Errorf("expected [a c] got %v (%v)", strs, err)
+	}
+	err = list.Remove(2)
+	if indexErr, ok := err.(*forge.IndexError); ok == false || indexErr.Index != 2 || indexErr.Length != 2 {
+		t.Errorf("expected an *IndexError got %v", err)
+	}
+}
+
+func TestListCloneEqual(t *testing.T) {
+	t.Parallel()
+
+	list := forge.NewList()
+	list.Append(forge.NewString("a"))
+	inner := forge.NewList()
+	inner.Append(forge.NewInteger(1))
+	list.Append(inner)
+
+	cloned := list.Clone().(*forge.List)
+	if cloned.Equal(list) == false {
+		t.Fatal("expected the clone to be equal to the original")
+	}
+
+	clonedInner, err := cloned.GetList(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clonedInner.Append(forge.NewInteger(2))
+	if inner.Length() != 1 {
+		t.Error("expected the original nested list to be unchanged")
+	}
+	if cloned.Equal(list) {
+		t.Error("expected lists of different lengths not to be equal")
+	}
+	if list.Equal(forge.NewString("a")) {
+		t.Error("expected a list not to be equal to a string")
+	}
+}
@@ -0,0 +1,222 @@
+package forge
+
+// ListStrategy is an int type for representing how `Section.MergeWithOptions` merges two Lists
+type ListStrategy int
+
+const (
+	// ListReplace replaces the elements of the target List with the elements of the source List
+	ListReplace ListStrategy = iota
+	// ListAppend appends the elements of the source List to the target List
+	ListAppend
+	// ListUnion appends the elements of the source List which are not Equal to an element of the target List
+	ListUnion
+)
+
+// MismatchPolicy is an int type for representing how `Section.MergeWithOptions` merges two values
+// which have different types, a Section, a List and a primative value all have different types
+// while primative values (e.g. a STRING and an INTEGER) can always replace each other
+type MismatchPolicy int
+
+const (
+	// MismatchError responds with a *MergeConflictError
+	MismatchError MismatchPolicy = iota
+	// MismatchReplace replaces the target value with the source value
+	MismatchReplace
+)
+
+// MergeStrategy describes how the values at a path are merged
+type MergeStrategy struct {
+	// Lists is how two Lists are merged
+	Lists ListStrategy
+	// TypeMismatch is how two values with different types are merged
+	TypeMismatch MismatchPolicy
+}
+
+// MergeOptions describes how `Section.MergeWithOptions` merges a source Section into a target Section,
+// the zero value merges the same way as `Section.Merge`
+type MergeOptions struct {
+	// Lists is how two Lists are merged
+	Lists ListStrategy
+	// TypeMismatch is how two values with different types are merged
+	TypeMismatch MismatchPolicy
+	// Paths overrides the strategy for the value at a path (e.g. "primary.hosts") and every value
+	// below it, paths are relative to the target Section
+	Paths map[string]MergeStrategy
+	// Final are the paths of values (e.g. "primary.secret") which keep their target value, they are
+	// only set from the source Section when the target Section does not have a value
+	Final []string
+}
+
+// merger merges Sections with the strategies of a MergeOptions
+type merger struct {
+	strategies map[string]MergeStrategy
+	final      map[string]bool
+	// sections maps every source Section being merged, from the source root down, to its target Section
+	sections map[*Section]*Section
+}
+
+func newMerger(options MergeOptions) (*merger, error) {
+	m := &merger{
+		strategies: make(map[string]MergeStrategy),
+		final:      make(map[string]bool),
+		sections:   make(map[*Section]*Section),
+	}
+
+	// Normalize every path so they can be compared to the formatted paths of the merged values
+	for path, strategy := range options.Paths {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, wrapError(err, "invalid merge path '%s'", path)
+		}
+		m.strategies[formatPath(segments)] = strategy
+	}
+	for _, path := range options.Final {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, wrapError(err, "invalid final path '%s'", path)
+		}
+		m.final[formatPath(segments)] = true
+	}
+	return m, nil
+}
+
+// MergeWithOptions merges the given section into the current section the same way as `Merge`,
+// with options describing how Lists, values with different types and final values are merged.
+// Will respond with a *FrozenError if a value which would change is frozen
+func (section *Section) MergeWithOptions(source *Section, options MergeOptions) error {
+	m, err := newMerger(options)
+	if err != nil {
+		return err
+	}
+
+	strategy := MergeStrategy{Lists: options.Lists, TypeMismatch: options.TypeMismatch}
+	return m.merge(section, source, nil, strategy)
+}
+
+// merge will merge the source Section into the target Section found at path
+func (m *merger) merge(target *Section, source *Section, path []pathSegment, strategy MergeStrategy) error {
+	m.sections[source] = target
+	for _, key := range source.OrderedKeys() {
+		keyPath := appendPath(path, pathSegment{key: key})
+		formatted := formatPath(keyPath)
+		keyStrategy := strategy
+		if override, ok := m.strategies[formatted]; ok {
+			keyStrategy = override
+		}
+
+		if target.frozen {
+			return &FrozenError{Path: formatted}
+		}
+
+		sourceValue := source.values[key]
+		targetValue, ok := target.values[key]
+
+		// not found, so add a copy of it
+		if ok == false {
+			target.setValue(key, m.clone(target, source, sourceValue))
+			mergeDefinitions(target, source, key)
+			continue
+		}
+
+		if m.final[formatted] {
+			continue
+		}
+
+		if mergeKind(targetValue) != mergeKind(sourceValue) {
+			if keyStrategy.TypeMismatch == MismatchReplace {
+				target.setValue(key, m.clone(target, source, sourceValue))
+				mergeDefinitions(target, source, key)
+				continue
+			}
+			return &MergeConflictError{
+				Path:   formatted,
+				Source: sourceValue.GetType(),
+				Target: targetValue.GetType(),
+			}
+		}
+
+		mergeDefinitions(target, source, key)
+
+		switch targetValue := targetValue.(type) {
+		case *Section:
+			if err := m.merge(targetValue, sourceValue.(*Section), keyPath, keyStrategy); err != nil {
+				return err
+			}
+		case *List:
+			if targetValue.frozen {
+				return &FrozenError{Path: formatted}
+			}
+			m.mergeList(targetValue, sourceValue.(*List), target, source, keyStrategy.Lists)
+		case *Primative:
+			if targetValue.frozen {
+				return &FrozenError{Path: formatted}
+			}
+			if sourcePrimative, ok := sourceValue.(*Primative); ok {
+				targetValue.valueType = sourcePrimative.valueType
+				targetValue.value = sourcePrimative.value
+			} else {
+				target.setValue(key, m.clone(target, source, sourceValue))
+			}
+		default:
+			target.setValue(key, m.clone(target, source, sourceValue))
+		}
+	}
+	return nil
+}
+
+// mergeList will merge the elements of the source List into the target List
+func (m *merger) mergeList(targetList *List, sourceList *List, target *Section, source *Section, strategy ListStrategy) {
+	if strategy == ListReplace {
+		targetList.values = make([]Value, 0, sourceList.Length())
+	}
+
+	for _, element := range sourceList.values {
+		if strategy == ListUnion {
+			found := false
+			for _, current := range targetList.values {
+				if current.Equal(element) {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+		targetList.Append(m.clone(target, source, element))
+	}
+}
+
+// clone will copy a value from the source Section to add it to the target Section, References which
+// are resolved from the source Section or any Section above it (e.g. global References resolved from
+// the source root) are resolved from the matching target Section instead
+func (m *merger) clone(target *Section, source *Section, value Value) Value {
+	c := newCloner()
+	for sourceSection, targetSection := range m.sections {
+		c.sections[sourceSection] = targetSection
+	}
+	c.sections[source] = target
+	return c.clone(value, target)
+}
+
+// mergeDefinitions will record that the value for key in the target Section was
+// set from the source Section, after every definition of it in the target Section
+func mergeDefinitions(target *Section, source *Section, key string) {
+	definitions := source.definitions[key]
+	if len(definitions) == 0 {
+		definitions = []Position{{}}
+	}
+	for _, position := range definitions {
+		target.define(key, position)
+	}
+}
+
+// mergeKind will return the type of value which must match to merge two values, every
+// primative value and every Reference is a NULL since they can always replace each other
+func mergeKind(value Value) ValueType {
+	switch value.GetType() {
+	case SECTION, LIST:
+		return value.GetType()
+	}
+	return NULL
+}
@@ -0,0 +1,207 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+const mergeTargetConfig = `
+hosts = ["a", "b"];
+tags = ["x"];
+db {
+  host = "localhost";
+  secret = "target";
+  ports = [1, 2];
+}
+mode = "simple";
+`
+
+const mergeSourceConfig = `
+hosts = ["b", "c"];
+tags = ["y"];
+db {
+  host = "remote";
+  secret = "source";
+  ports = [2, 3];
+}
+mode { name = "complex"; }
+`
+
+func parseMergeConfigs(t *testing.T) (*forge.Section, *forge.Section) {
+	target, err := forge.ParseString(mergeTargetConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := forge.ParseString(mergeSourceConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target, source
+}
+
+func assertStrings(section *forge.Section, path string, expected []string, t *testing.T) {
+	list, err := section.ResolveList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := list.Strings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != len(expected) {
+		t.Errorf("expected %s to be %v got %v", path, expected, values)
+		return
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Errorf("expected %s to be %v got %v", path, expected, values)
+			return
+		}
+	}
+}
+
+func TestMergeListsReplace(t *testing.T) {
+	t.Parallel()
+
+	target, source := parseMergeConfigs(t)
+	source.Delete("mode")
+	if err := target.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(target, "hosts", []string{"b", "c"}, t)
+	assertStrings(target, "db.ports", []string{"2", "3"}, t)
+}
+
+func TestMergeWithOptionsLists(t *testing.T) {
+	t.Parallel()
+
+	target, source := parseMergeConfigs(t)
+	err := target.MergeWithOptions(source, forge.MergeOptions{
+		Lists:        forge.ListAppend,
+		TypeMismatch: forge.MismatchReplace,
+		Paths: map[string]forge.MergeStrategy{
+			"db":   {Lists: forge.ListUnion},
+			"tags": {Lists: forge.ListReplace},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(target, "hosts", []string{"a", "b", "b", "c"}, t)
+	assertStrings(target, "tags", []string{"y"}, t)
+	assertStrings(target, "db.ports", []string{"1", "2", "3"}, t)
+
+	// The source list is copied
+	hosts, err := source.GetList("hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts.Set(0, forge.NewString("changed"))
+	assertStrings(target, "hosts", []string{"a", "b", "b", "c"}, t)
+}
+
+func TestMergeWithOptionsTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	target, source := parseMergeConfigs(t)
+	err := target.MergeWithOptions(source, forge.MergeOptions{})
+	conflict, ok := err.(*forge.MergeConflictError)
+	if ok == false || conflict.Path != "mode" || conflict.Source != forge.SECTION || conflict.Target != forge.STRING {
+		t.Errorf("expected a *MergeConflictError for 'mode' got %v", err)
+	}
+
+	target, source = parseMergeConfigs(t)
+	err = target.MergeWithOptions(source, forge.MergeOptions{TypeMismatch: forge.MismatchReplace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := target.ResolveString("mode.name")
+	if err != nil || name != "complex" {
+		t.Errorf("expected 'complex' got %s (%v)", name, err)
+	}
+	mode, err := target.GetSection("mode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode.GetParent() != target {
+		t.Error("expected the replaced section to belong to the target")
+	}
+
+	// Per path overrides
+	target, source = parseMergeConfigs(t)
+	err = target.MergeWithOptions(source, forge.MergeOptions{
+		TypeMismatch: forge.MismatchReplace,
+		Paths:        map[string]forge.MergeStrategy{"mode": {TypeMismatch: forge.MismatchError}},
+	})
+	if _, ok := err.(*forge.MergeConflictError); ok == false {
+		t.Errorf("expected a *MergeConflictError got %v", err)
+	}
+}
+
+func TestMergeWithOptionsFinal(t *testing.T) {
+	t.Parallel()
+
+	target, source := parseMergeConfigs(t)
+	source.SetString("added", "value")
+	err := target.MergeWithOptions(source, forge.MergeOptions{
+		TypeMismatch: forge.MismatchReplace,
+		Final:        []string{"db.secret", "hosts", "added"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := target.ResolveString("db.secret")
+	if err != nil || secret != "target" {
+		t.Errorf("expected 'target' got %s (%v)", secret, err)
+	}
+	host, err := target.ResolveString("db.host")
+	if err != nil || host != "remote" {
+		t.Errorf("expected 'remote' got %s (%v)", host, err)
+	}
+	assertStrings(target, "hosts", []string{"a", "b"}, t)
+
+	// Final values which do not exist are still added
+	added, err := target.GetString("added")
+	if err != nil || added != "value" {
+		t.Errorf("expected 'value' got %s (%v)", added, err)
+	}
+
+	if err = target.MergeWithOptions(source, forge.MergeOptions{Final: []string{"hosts[x]"}}); err == nil {
+		t.Error("expected an error for an invalid final path")
+	}
+}
+
+func TestMergeNestedGlobalReferences(t *testing.T) {
+	t.Parallel()
+
+	target, err := forge.ParseString("db { port = 5432; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := forge.ParseString(`
+host = "source";
+top = host;
+db { url = host; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = target.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+
+	// Global references in nested sections are resolved from the target root
+	target.SetString("host", "changed")
+	for _, path := range []string{"top", "db.url"} {
+		value, err := target.ResolveString(path)
+		if err != nil || value != "changed" {
+			t.Errorf("expected %s to be 'changed' got %s (%v)", path, value, err)
+		}
+	}
+	value, err := source.ResolveString("db.url")
+	if err != nil || value != "source" {
+		t.Errorf("expected the source to be unchanged got %s (%v)", value, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/brettlangdon/forge/token"
 )
@@ -15,14 +16,44 @@ func isSemicolonOrNewline(id token.TokenID) bool {
 	return id == token.SEMICOLON || id == token.NEWLINE
 }
 
+func isDirectiveEnd(id token.TokenID) bool {
+	return isSemicolonOrNewline(id) || id == token.COMMENT
+}
+
+func isValueEnd(id token.TokenID) bool {
+	return isDirectiveEnd(id) || id == token.COMMA || id == token.RBRACKET || id == token.RBRACE
+}
+
+// isKey returns true for any token which can be used as the name of a setting or section
+// DEV: Keywords are allowed as key names, e.g. `true = 1` or `"include" { }`
+func isKey(id token.TokenID) bool {
+	switch id {
+	case token.IDENTIFIER, token.STRING, token.BOOLEAN, token.NULL, token.INCLUDE:
+		return true
+	}
+	return false
+}
+
 // Parser is a struct to hold data necessary for parsing a config from a scanner
 type Parser struct {
 	files      []string
+	patterns   []string
+	curFile    string
 	settings   *Section
 	scanner    *Scanner
 	curTok     token.Token
 	curSection *Section
 	previous   []*Section
+	owners     []*Section
+	names      []string
+
+	// Used for associating comments with the key they document
+	pendingComments []string
+	lastSection     *Section
+	lastKey         string
+	lastLine        int
+	// lastClosed is true when the last key is a section which was just closed
+	lastClosed bool
 }
 
 // NewParser will create and initialize a new Parser from a provided io.Reader
@@ -34,6 +65,8 @@ func NewParser(reader io.Reader) *Parser {
 		settings:   settings,
 		curSection: settings,
 		previous:   make([]*Section, 0),
+		owners:     make([]*Section, 0),
+		names:      make([]string, 0),
 	}
 }
 
@@ -45,6 +78,7 @@ func NewFileParser(filename string) (*Parser, error) {
 	}
 	parser := NewParser(reader)
 	parser.addFile(filename)
+	parser.curFile = filename
 	return parser, nil
 }
 
@@ -71,6 +105,25 @@ func (parser *Parser) syntaxError(msg string) error {
 	return errors.New(msg)
 }
 
+// position will return the Position of the current token in the file being parsed
+// DEV: The scanner counts lines from 0 and only counts columns from 1 on the first line
+func (parser *Parser) position() Position {
+	return parser.tokenPosition(parser.curTok)
+}
+
+// tokenPosition will convert the line and column of tok into a Position in the current file
+func (parser *Parser) tokenPosition(tok token.Token) Position {
+	column := tok.Column
+	if tok.Line > 0 {
+		column++
+	}
+	return Position{
+		Filename: parser.curFile,
+		Line:     tok.Line + 1,
+		Column:   column,
+	}
+}
+
 func (parser *Parser) readToken() token.Token {
 	parser.curTok = parser.scanner.NextToken()
 	return parser.curTok
@@ -82,6 +135,36 @@ func (parser *Parser) skipNewlines() {
 	}
 }
 
+// markKey records that the key name was defined on the provided line in the owner section
+// and attaches any comments which were seen directly before it
+func (parser *Parser) markKey(owner *Section, name string, line int) {
+	for _, comment := range parser.pendingComments {
+		owner.AddLeadingComment(name, comment)
+	}
+	parser.pendingComments = nil
+
+	parser.lastSection = owner
+	parser.lastKey = name
+	parser.lastLine = line
+	parser.lastClosed = false
+}
+
+// addComment will either attach the comment as the trailing comment of the key defined
+// on the same line or hold onto it as a leading comment for the next key defined
+func (parser *Parser) addComment(tok token.Token) {
+	parser.curSection.AddComment(tok.Literal)
+	if parser.lastSection != nil && parser.lastLine == tok.Line {
+		// A comment after a closing brace only documents the section when the opening brace had none
+		comments, ok := parser.lastSection.keyComments[parser.lastKey]
+		if parser.lastClosed == false || ok == false || comments.Trailing == "" {
+			parser.lastSection.SetTrailingComment(parser.lastKey, tok.Literal)
+		}
+	} else {
+		parser.pendingComments = append(parser.pendingComments, tok.Literal)
+	}
+	parser.lastSection = nil
+}
+
 func (parser *Parser) parseList() ([]Value, error) {
 	var values []Value
 	for {
@@ -108,25 +191,50 @@ func (parser *Parser) parseList() ([]Value, error) {
 	return values, nil
 }
 
-func (parser *Parser) parseReference(startingSection *Section, period bool) (Value, error) {
-	name := ""
-	if period == false {
-		name = parser.curTok.Literal
+// parseReferenceIndex parses the index access of a reference, e.g. the `[0]` in `servers[0].host`
+func (parser *Parser) parseReferenceIndex() (string, error) {
+	parser.readToken()
+	if parser.curTok.ID != token.INTEGER {
+		msg := fmt.Sprintf("expected INTEGER instead found '%s'", parser.curTok.Literal)
+		return "", parser.syntaxError(msg)
+	}
+	index := parser.curTok.Literal
+
+	parser.readToken()
+	if parser.curTok.ID != token.RBRACKET {
+		msg := fmt.Sprintf("expected ']' instead found '%s'", parser.curTok.Literal)
+		return "", parser.syntaxError(msg)
 	}
+	return "[" + index + "]", nil
+}
+
+func (parser *Parser) parseReference(startingSection *Section, name string, period bool) (Value, error) {
+	// Local references, e.g. `.key` or `..key`, start with a period instead of a name
+	local := len(name) == 0
+	// The number of parent sections to step up for references like `..key`
+	parents := 0
 	for {
 		parser.readToken()
 		if parser.curTok.ID == token.PERIOD && period == false {
 			period = true
-		} else if period && parser.curTok.ID == token.IDENTIFIER {
+		} else if parser.curTok.ID == token.PERIOD && period && len(name) == 0 {
+			parents++
+		} else if period && isKey(parser.curTok.ID) {
 			if len(name) > 0 {
 				name += "."
 			}
-			name += parser.curTok.Literal
+			name += EscapeKey(parser.curTok.Literal)
 			period = false
-		} else if isSemicolonOrNewline(parser.curTok.ID) {
+		} else if parser.curTok.ID == token.LBRACKET && period == false && len(name) > 0 {
+			index, err := parser.parseReferenceIndex()
+			if err != nil {
+				return nil, err
+			}
+			name += index
+		} else if isValueEnd(parser.curTok.ID) {
 			break
 		} else {
-			msg := fmt.Sprintf("expected ';' or '\n' instead found '%s'", parser.curTok.Literal)
+			msg := fmt.Sprintf("expected ';', ',', ']', '}' or '\n' instead found '%s'", parser.curTok.Literal)
 			return nil, parser.syntaxError(msg)
 		}
 	}
@@ -140,16 +248,32 @@ func (parser *Parser) parseReference(startingSection *Section, period bool) (Val
 		return nil, parser.syntaxError(fmt.Sprintf("expected IDENTIFIER after PERIOD"))
 	}
 
+	if local {
+		name = strings.Repeat(".", parents+1) + name
+	}
 	return NewReference(name, startingSection), nil
 }
 
 func (parser *Parser) parseSettingValue() (Value, error) {
 	var value Value
+	position := parser.position()
 
 	readNext := true
 	switch parser.curTok.ID {
 	case token.STRING:
-		value = NewString(parser.curTok.Literal)
+		literal := parser.curTok.Literal
+		parser.readToken()
+		// A quoted key followed by a period is the start of a global reference, e.g. "10.0.0.1".port
+		if parser.curTok.ID == token.PERIOD {
+			reference, err := parser.parseReference(parser.settings, EscapeKey(literal), true)
+			if err != nil {
+				return value, err
+			}
+			value = reference
+		} else {
+			value = NewString(literal)
+		}
+		readNext = false
 	case token.BOOLEAN:
 		boolVal, err := strconv.ParseBool(parser.curTok.Literal)
 		if err != nil {
@@ -171,14 +295,14 @@ func (parser *Parser) parseSettingValue() (Value, error) {
 		}
 		value = NewFloat(floatVal)
 	case token.PERIOD:
-		reference, err := parser.parseReference(parser.curSection, true)
+		reference, err := parser.parseReference(parser.curSection, "", true)
 		if err != nil {
 			return value, err
 		}
 		value = reference
 		readNext = false
 	case token.IDENTIFIER:
-		reference, err := parser.parseReference(parser.settings, false)
+		reference, err := parser.parseReference(parser.settings, EscapeKey(parser.curTok.Literal), false)
 		if err != nil {
 			return value, err
 		}
@@ -202,22 +326,72 @@ func (parser *Parser) parseSettingValue() (Value, error) {
 	if readNext {
 		parser.readToken()
 	}
+	if reference, ok := value.(*Reference); ok {
+		reference.position = position
+	}
 	return value, nil
 }
 
-func (parser *Parser) parseSetting(name string) error {
+// parseValue will parse data as a single value (e.g. `5433`, `true` or `["a", "b"]`), it is
+// an error if data contains anything after the value or if the value contains a reference
+func parseValue(data string) (Value, error) {
+	parser := NewParser(strings.NewReader(data))
+	parser.readToken()
+	value, err := parser.parseSettingValue()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipNewlines()
+	if parser.curTok.ID != token.EOF {
+		return nil, parser.syntaxError(fmt.Sprintf("unexpected '%s' after value", parser.curTok.Literal))
+	}
+	if containsReference(value) {
+		return nil, errors.New("value must not contain a reference")
+	}
+	return value, nil
+}
+
+// containsReference will return true if value is a Reference or a List which contains a Reference
+func containsReference(value Value) bool {
+	switch value := value.(type) {
+	case *Reference:
+		return true
+	case *List:
+		for _, element := range value.values {
+			if containsReference(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (parser *Parser) parseSetting(owner *Section, name string, position Position) error {
 	parser.readToken()
 	value, err := parser.parseSettingValue()
 	if err != nil {
 		return err
 	}
-	if isSemicolonOrNewline(parser.curTok.ID) == false {
+	// A closing brace ends both the directive and the section, e.g. `section { key = value }`
+	if parser.curTok.ID == token.RBRACE {
+		parser.markKey(owner, name, parser.curTok.Line)
+		owner.setValue(name, value)
+		owner.define(name, position)
+		return nil
+	}
+	if isDirectiveEnd(parser.curTok.ID) == false {
 		msg := fmt.Sprintf("expected ';' or '\n' instead found '%s'", parser.curTok.Literal)
 		return parser.syntaxError(msg)
 	}
+	parser.markKey(owner, name, parser.curTok.Line)
+	// A comment directly after the value both ends the directive and documents it
+	if parser.curTok.ID == token.COMMENT {
+		parser.addComment(parser.curTok)
+	}
 	parser.readToken()
 
-	parser.curSection.Set(name, value)
+	owner.setValue(name, value)
+	owner.define(name, position)
 	return nil
 }
 
@@ -234,11 +408,13 @@ func (parser *Parser) parseInclude() error {
 		return parser.syntaxError(msg)
 	}
 
+	parser.patterns = append(parser.patterns, pattern)
 	filenames, err := filepath.Glob(pattern)
 	if err != nil {
 		return err
 	}
 	oldScanner := parser.scanner
+	oldFile := parser.curFile
 	for _, filename := range filenames {
 		// We have already visited this file, don't include again
 		// DEV: This can cause recursive includes if this isn't here :o
@@ -252,32 +428,134 @@ func (parser *Parser) parseInclude() error {
 		}
 		parser.curSection.AddInclude(filename)
 		parser.scanner = NewScanner(reader)
-		parser.parse()
+		parser.curFile = filename
+		if err = parser.parse(); err != nil {
+			return err
+		}
 		// Make sure to add the filename to the internal list to ensure we don't
 		// accidentally recursively include config files
 		parser.addFile(filename)
 	}
 	parser.scanner = oldScanner
+	parser.curFile = oldFile
+	// Comments directly before an include document the include rather than a key
+	parser.pendingComments = nil
+	parser.lastSection = nil
 	parser.readToken()
 	return nil
 }
 
-func (parser *Parser) parseSection(name string) error {
-	section := parser.curSection.AddSection(name)
+// resolveKeyPath will find the section which the last of the keys should be defined in,
+// any intermediate sections which do not exist yet are created
+func (parser *Parser) resolveKeyPath(keys []string, position Position) (*Section, error) {
+	section := parser.curSection
+	for i, key := range keys[:len(keys)-1] {
+		value, ok := section.values[key]
+		if ok == false {
+			created := section.addSection(key)
+			section.define(key, position)
+			section = created
+			continue
+		}
+
+		if value.GetType() != SECTION {
+			return nil, parser.notSectionError(keys, i, value)
+		}
+		section = value.(*Section)
+	}
+	return section, nil
+}
+
+// notSectionError will respond with a syntax error for assigning the keys through
+// the value at keys[i], which is not a Section
+func (parser *Parser) notSectionError(keys []string, i int, value Value) error {
+	msg := fmt.Sprintf(
+		"cannot assign '%s', '%s' is a %s not a SECTION",
+		JoinPath(keys...), JoinPath(keys[:i+1]...), value.GetType(),
+	)
+	return parser.syntaxError(msg)
+}
+
+func (parser *Parser) parseKey(name string, position Position) error {
+	// Dotted keys, e.g. `db.primary.host = "x"`, define the last key in nested sections
+	keys := []string{name}
+	for parser.curTok.ID == token.PERIOD {
+		parser.readToken()
+		if isKey(parser.curTok.ID) == false {
+			return parser.syntaxError("expected IDENTIFIER after PERIOD")
+		}
+		keys = append(keys, parser.curTok.Literal)
+		parser.readToken()
+	}
+
+	if parser.curTok.ID != token.LBRACE && parser.curTok.ID != token.EQUAL {
+		msg := fmt.Sprintf("expected '=' or '{' instead found '%s'", parser.curTok.Literal)
+		return parser.syntaxError(msg)
+	}
+
+	owner, err := parser.resolveKeyPath(keys, position)
+	if err != nil {
+		return err
+	}
+	name = keys[len(keys)-1]
+
+	if parser.curTok.ID == token.EQUAL {
+		return parser.parseSetting(owner, name, position)
+	}
+
+	err = parser.parseSection(owner, keys, position)
+	if err != nil {
+		return err
+	}
+	parser.readToken()
+	return nil
+}
+
+func (parser *Parser) parseSection(owner *Section, keys []string, position Position) error {
+	name := keys[len(keys)-1]
+
+	// A block for a section which already exists (e.g. created by a dotted key) extends it
+	var section *Section
+	if value, ok := owner.values[name]; ok {
+		existing, isSection := value.(*Section)
+		if isSection == false {
+			return parser.notSectionError(keys, len(keys)-1, value)
+		}
+		section = existing
+	} else {
+		section = owner.addSection(name)
+	}
+
+	parser.markKey(owner, name, parser.curTok.Line)
+	owner.define(name, position)
 	parser.previous = append(parser.previous, parser.curSection)
+	parser.owners = append(parser.owners, owner)
+	parser.names = append(parser.names, name)
 	parser.curSection = section
 	return nil
 }
 
-func (parser *Parser) endSection() error {
+func (parser *Parser) endSection(line int) error {
 	if len(parser.previous) == 0 {
 		return parser.syntaxError("unexpected section end '}'")
 	}
 
 	pLen := len(parser.previous)
 	previous := parser.previous[pLen-1]
+	owner := parser.owners[pLen-1]
+	name := parser.names[pLen-1]
 	parser.previous = parser.previous[0 : pLen-1]
+	parser.owners = parser.owners[0 : pLen-1]
+	parser.names = parser.names[0 : pLen-1]
 	parser.curSection = previous
+
+	// Comments left over at the end of a section do not document any key,
+	// but a comment after the closing brace documents the section itself
+	parser.pendingComments = nil
+	parser.lastSection = owner
+	parser.lastKey = name
+	parser.lastLine = line
+	parser.lastClosed = true
 	return nil
 }
 
@@ -291,29 +569,33 @@ func (parser *Parser) parse() error {
 		parser.readToken()
 		switch tok.ID {
 		case token.COMMENT:
-			parser.curSection.AddComment(tok.Literal)
+			parser.addComment(tok)
 		case token.INCLUDE:
-			parser.parseInclude()
-		case token.IDENTIFIER:
-			if parser.curTok.ID == token.LBRACE {
-				err := parser.parseSection(tok.Literal)
-				if err != nil {
-					return err
-				}
-				parser.readToken()
-			} else if parser.curTok.ID == token.EQUAL {
-				err := parser.parseSetting(tok.Literal)
-				if err != nil {
+			// `include` is only a key name when followed by '=' or '{'
+			if parser.curTok.ID != token.EQUAL && parser.curTok.ID != token.LBRACE {
+				if err := parser.parseInclude(); err != nil {
 					return err
 				}
+				continue
+			}
+			err := parser.parseKey(tok.Literal, parser.tokenPosition(tok))
+			if err != nil {
+				return err
+			}
+		case token.IDENTIFIER, token.STRING, token.BOOLEAN, token.NULL:
+			err := parser.parseKey(tok.Literal, parser.tokenPosition(tok))
+			if err != nil {
+				return err
 			}
 		case token.RBRACE:
-			err := parser.endSection()
+			err := parser.endSection(tok.Line)
 			if err != nil {
 				return err
 			}
 		case token.NEWLINE:
-			// Ignore extra newlines
+			// Comments include their newline, so this is a blank line which
+			// separates any comments before it from the next key
+			parser.pendingComments = nil
 			continue
 		default:
 			return parser.syntaxError(fmt.Sprintf("unexpected token %s", tok))
@@ -0,0 +1,217 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step of a path, either a key in a Section, an index into a List
+// or a step up to the parent of a Section, see `formatPath` for converting back to a path
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	isParent bool
+}
+
+func (segment pathSegment) String() string {
+	if segment.isIndex {
+		return "[" + strconv.Itoa(segment.index) + "]"
+	}
+	return EscapeKey(segment.key)
+}
+
+// EscapeKey will escape any periods, brackets and backslashes in the key name so that
+// it can be used as a single segment of a path passed to `Section.Resolve`
+// (e.g. "10.0.0.1" becomes "10\.0\.0\.1")
+func EscapeKey(key string) string {
+	escaped := ""
+	for _, ch := range key {
+		switch ch {
+		case '\\', '.', '[', ']':
+			escaped += "\\"
+		}
+		escaped += string(ch)
+	}
+	return escaped
+}
+
+// JoinPath will escape and join the provided key names into a single path
+// which can be passed to `Section.Resolve`
+func JoinPath(keys ...string) string {
+	escaped := make([]string, len(keys))
+	for i, key := range keys {
+		escaped[i] = EscapeKey(key)
+	}
+	return strings.Join(escaped, ".")
+}
+
+// SplitPath will split the provided path on every unescaped period and will
+// unescape each of the resulting key names, this is the inverse of `JoinPath`
+//
+// DEV: Index access (e.g. `servers[0]`) is not interpreted and is left as part of the key name
+func SplitPath(path string) []string {
+	var keys []string
+	key := ""
+	escape := false
+	for _, ch := range path {
+		if escape {
+			key += string(ch)
+			escape = false
+		} else if ch == '\\' {
+			escape = true
+		} else if ch == '.' {
+			keys = append(keys, key)
+			key = ""
+		} else {
+			key += string(ch)
+		}
+	}
+	// A trailing backslash has nothing to escape, keep it as is
+	if escape {
+		key += "\\"
+	}
+	return append(keys, key)
+}
+
+// parsePath will parse the provided path into the key and index segments it is made up of
+// (e.g. `servers[0].host` becomes `servers`, `[0]`, `host`)
+//
+// A path may start with periods, a single period refers to the current section and every
+// additional period steps up to the parent section (e.g. `..key` is `key` in the parent section)
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	key := ""
+	escape := false
+	// Whether the key being read has been ended by an index, e.g. `servers[0]`
+	indexed := false
+
+	runes := []rune(path)
+	start := 0
+	for start < len(runes) && runes[start] == '.' {
+		if start > 0 {
+			segments = append(segments, pathSegment{isParent: true})
+		}
+		start++
+	}
+	// A path of only periods refers to a parent section itself, e.g. `..`
+	if start == len(runes) && len(segments) > 0 {
+		return segments, nil
+	}
+
+	for i := start; i < len(runes); i++ {
+		ch := runes[i]
+		if escape {
+			key += string(ch)
+			escape = false
+			continue
+		}
+
+		switch ch {
+		case '\\':
+			escape = true
+		case '.':
+			if indexed == false {
+				segments = append(segments, pathSegment{key: key})
+			}
+			key = ""
+			indexed = false
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("missing ']' in path '%s'", path)
+			}
+			index, err := strconv.Atoi(string(runes[i+1 : end]))
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index '%s' in path '%s'", string(runes[i+1:end]), path)
+			}
+
+			// The key before the first index, e.g. the `servers` in `servers[0][1]`
+			if indexed == false && (key != "" || i > 0) {
+				segments = append(segments, pathSegment{key: key})
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			key = ""
+			indexed = true
+			i = end
+		default:
+			if indexed {
+				return nil, fmt.Errorf("expected '.' or '[' after index in path '%s'", path)
+			}
+			key += string(ch)
+		}
+	}
+
+	if escape {
+		key += "\\"
+	}
+	if indexed == false {
+		segments = append(segments, pathSegment{key: key})
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.New("no name provided")
+	}
+	return segments, nil
+}
+
+// formatPath will join the segments back into a path which can be passed to `Section.Resolve`
+func formatPath(segments []pathSegment) string {
+	path := ""
+	for i, segment := range segments {
+		if segment.isParent {
+			// Leading periods, the first for the current section and one more per parent
+			if i == 0 {
+				path += "."
+			}
+			path += "."
+			continue
+		}
+
+		if i > 0 && segment.isIndex == false && segments[i-1].isParent == false {
+			path += "."
+		}
+		path += segment.String()
+	}
+	return path
+}
+
+// sectionPath will return the segments of the path from the root section to the provided section
+func sectionPath(section *Section) []pathSegment {
+	var segments []pathSegment
+	for child := section; child.parent != nil; child = child.parent {
+		for key, value := range child.parent.values {
+			if value == Value(child) {
+				segments = append([]pathSegment{{key: key}}, segments...)
+				break
+			}
+		}
+	}
+	return segments
+}
+
+// absolutePath will return the path from the root section of the value name refers to
+// when resolved from the provided section, if name cannot be parsed it is returned as is
+func absolutePath(section *Section, name string) string {
+	relative, err := parsePath(name)
+	if err != nil {
+		return name
+	}
+
+	segments := sectionPath(section)
+	for _, segment := range relative {
+		if segment.isParent {
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return formatPath(segments)
+}
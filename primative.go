@@ -5,10 +5,36 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"sync/atomic"
 )
 
+// maxExactFloatInteger is the largest integer which a float64 can hold without losing precision
+const maxExactFloatInteger = 1 << 53
+
+var strictTypes int32
+
+// SetStrictTypes will enable or disable strict type conversion for every value.
+//
+// When enabled the `Primative.As*` methods and all getters only convert a value which has the
+// requested ValueType, or an INTEGER to a FLOAT or a whole FLOAT to an INTEGER when no precision
+// is lost, and respond with a *TypeError otherwise. Strict type conversion can also be enabled
+// for a single tree of Sections with `Section.SetStrictTypes`
+func SetStrictTypes(strict bool) {
+	value := int32(0)
+	if strict {
+		value = 1
+	}
+	atomic.StoreInt32(&strictTypes, value)
+}
+
+// StrictTypes will return true if strict type conversion was enabled with `SetStrictTypes`
+func StrictTypes() bool {
+	return atomic.LoadInt32(&strictTypes) == 1
+}
+
 // Primative struct for holding data about primative values
 type Primative struct {
+	frozen    bool
 	valueType ValueType
 	value     interface{}
 }
@@ -20,6 +46,20 @@ func newPrimative(valueType ValueType, value interface{}) *Primative {
 	}
 }
 
+// Clone will return a new Primative with the same type and value
+func (primative *Primative) Clone() Value {
+	return newPrimative(primative.valueType, primative.value)
+}
+
+// Equal will return true if other is a Primative with the same type and value
+func (primative *Primative) Equal(other Value) bool {
+	otherPrimative, ok := other.(*Primative)
+	if ok == false {
+		return false
+	}
+	return primative.valueType == otherPrimative.valueType && primative.value == otherPrimative.value
+}
+
 // NewPrimative will create a new empty Primative and call UpdateValue
 // with the provided value
 func NewPrimative(value interface{}) (*Primative, error) {
@@ -64,7 +104,12 @@ func (primative *Primative) GetValue() interface{} {
 }
 
 // UpdateValue will update the internal value and stored ValueType for this primative
+// will respond with a *FrozenError if the primative is frozen
 func (primative *Primative) UpdateValue(value interface{}) error {
+	if primative.frozen {
+		return &FrozenError{}
+	}
+
 	// Valid types
 	switch value.(type) {
 	case bool:
@@ -89,8 +134,26 @@ func (primative *Primative) UpdateValue(value interface{}) error {
 	return nil
 }
 
+func (primative *Primative) typeError(want ValueType) error {
+	return &TypeError{
+		Want: want,
+		Got:  primative.valueType,
+	}
+}
+
 // AsBoolean tries to convert/return the value stored in this primative as a bool
 func (primative *Primative) AsBoolean() (bool, error) {
+	return primative.asBoolean(StrictTypes())
+}
+
+func (primative *Primative) asBoolean(strict bool) (bool, error) {
+	if strict {
+		if val, ok := primative.value.(bool); ok {
+			return val, nil
+		}
+		return false, primative.typeError(BOOLEAN)
+	}
+
 	switch val := primative.value.(type) {
 	case bool:
 		return val, nil
@@ -104,12 +167,27 @@ func (primative *Primative) AsBoolean() (bool, error) {
 		return val != "", nil
 	}
 
-	msg := fmt.Sprintf("Could not convert value %s to type BOOLEAN", primative.value)
-	return false, errors.New(msg)
+	return false, primative.typeError(BOOLEAN)
 }
 
 // AsFloat tries to convert/return the value stored in this primative as a float64
 func (primative *Primative) AsFloat() (float64, error) {
+	return primative.asFloat(StrictTypes())
+}
+
+func (primative *Primative) asFloat(strict bool) (float64, error) {
+	if strict {
+		switch val := primative.value.(type) {
+		case float64:
+			return val, nil
+		case int64:
+			if -maxExactFloatInteger <= val && val <= maxExactFloatInteger {
+				return float64(val), nil
+			}
+		}
+		return 0, primative.typeError(FLOAT)
+	}
+
 	switch val := primative.value.(type) {
 	case bool:
 		floatVal := float64(0)
@@ -126,12 +204,28 @@ func (primative *Primative) AsFloat() (float64, error) {
 		return strconv.ParseFloat(val, 64)
 	}
 
-	msg := fmt.Sprintf("Could not convert value %s to type FLOAT", primative.value)
-	return 0, errors.New(msg)
+	return 0, primative.typeError(FLOAT)
 }
 
 // AsInteger tries to convert/return the value stored in this primative as a int64
 func (primative *Primative) AsInteger() (int64, error) {
+	return primative.asInteger(StrictTypes())
+}
+
+func (primative *Primative) asInteger(strict bool) (int64, error) {
+	if strict {
+		switch val := primative.value.(type) {
+		case int64:
+			return val, nil
+		case float64:
+			// DEV: float64(math.MaxInt64) rounds up to 2^63, which does not fit in an int64
+			if val == math.Trunc(val) && math.MinInt64 <= val && val < math.MaxInt64 {
+				return int64(val), nil
+			}
+		}
+		return 0, primative.typeError(INTEGER)
+	}
+
 	switch val := primative.value.(type) {
 	case bool:
 		intVal := int64(0)
@@ -147,8 +241,7 @@ func (primative *Primative) AsInteger() (int64, error) {
 		return strconv.ParseInt(val, 10, 64)
 	}
 
-	msg := fmt.Sprintf("Could not convert value %s to type INTEGER", primative.value)
-	return 0, errors.New(msg)
+	return 0, primative.typeError(INTEGER)
 }
 
 // AsNull tries to convert/return the value stored in this primative as a null
@@ -158,12 +251,22 @@ func (primative *Primative) AsNull() (interface{}, error) {
 		return val, nil
 	}
 
-	msg := fmt.Sprintf("Could not convert value %s to nil", primative.value)
-	return 0, errors.New(msg)
+	return nil, primative.typeError(NULL)
 }
 
 // AsString tries to convert/return the value stored in this primative as a string
 func (primative *Primative) AsString() (string, error) {
+	return primative.asString(StrictTypes())
+}
+
+func (primative *Primative) asString(strict bool) (string, error) {
+	if strict {
+		if val, ok := primative.value.(string); ok {
+			return val, nil
+		}
+		return "", primative.typeError(STRING)
+	}
+
 	switch val := primative.value.(type) {
 	case bool:
 		strVal := "False"
@@ -181,11 +284,10 @@ func (primative *Primative) AsString() (string, error) {
 		return val, nil
 	}
 
-	msg := fmt.Sprintf("Could not convert value %s to type STRING", primative.value)
-	return "", errors.New(msg)
+	return "", primative.typeError(STRING)
 }
 
 func (primative *Primative) String() string {
-	str, _ := primative.AsString()
+	str, _ := primative.asString(false)
 	return str
 }
@@ -405,3 +405,67 @@ func TestAsBoolean(t *testing.T) {
 		return
 	}
 }
+
+// DEV: This test is not run in parallel since it changes the global strict type setting
+func TestPrimativeStrictTypes(t *testing.T) {
+	forge.SetStrictTypes(true)
+	defer forge.SetStrictTypes(false)
+
+	_, err := forge.NewString("false").AsBoolean()
+	typeErr, ok := err.(*forge.TypeError)
+	if ok == false {
+		t.Fatalf("expected a *TypeError, got %v", err)
+	}
+	if typeErr.Want != forge.BOOLEAN || typeErr.Got != forge.STRING {
+		t.Errorf("expected BOOLEAN and STRING types, got %s and %s", typeErr.Want, typeErr.Got)
+	}
+
+	if _, err = forge.NewBoolean(true).AsString(); err == nil {
+		t.Error("expected an error converting a BOOLEAN to a string")
+	}
+	if _, err = forge.NewFloat(50.5).AsInteger(); err == nil {
+		t.Error("expected an error converting a fractional FLOAT to an integer")
+	}
+	if _, err = forge.NewString("50").AsInteger(); err == nil {
+		t.Error("expected an error converting a STRING to an integer")
+	}
+
+	// Lossless conversions are still allowed
+	integer, err := forge.NewFloat(50).AsInteger()
+	if err != nil || integer != 50 {
+		t.Errorf("expected 50 got %d (%v)", integer, err)
+	}
+	float, err := forge.NewInteger(50).AsFloat()
+	if err != nil || float != 50 {
+		t.Errorf("expected 50 got %f (%v)", float, err)
+	}
+	if _, err = forge.NewInteger(1<<60 + 1).AsFloat(); err == nil {
+		t.Error("expected an error converting an INTEGER which loses precision to a float")
+	}
+}
+
+func TestPrimativeCloneEqual(t *testing.T) {
+	t.Parallel()
+
+	value := forge.NewString("hello")
+	cloned := value.Clone()
+	if cloned.Equal(value) == false {
+		t.Fatal("expected the clone to be equal to the original")
+	}
+
+	if err := cloned.UpdateValue("world"); err != nil {
+		t.Fatal(err)
+	}
+	if value.GetValue().(string) != "hello" {
+		t.Error("expected the original to be unchanged")
+	}
+	if cloned.Equal(value) {
+		t.Error("expected different strings not to be equal")
+	}
+	if forge.NewInteger(1).Equal(forge.NewFloat(1)) {
+		t.Error("expected values of different types not to be equal")
+	}
+	if forge.NewNull().Equal(forge.NewNull()) == false {
+		t.Error("expected nulls to be equal")
+	}
+}
@@ -0,0 +1,76 @@
+package forge
+
+import (
+	"fmt"
+)
+
+// Provenance describes where a value was defined and every earlier definition it overrode,
+// either by being defined again later in a config, in an included config or by `Section.Merge`
+type Provenance struct {
+	// Path is the path of the value (e.g. "primary.host")
+	Path string
+	// Position is where the value was last set, it is not valid when the value was
+	// last set with the Section api (e.g. `Section.SetString`) rather than parsed
+	Position Position
+	// Overridden are the positions of the earlier definitions of the value, the most recent first
+	Overridden []Position
+}
+
+// Provenance will respond with where the value at path (e.g. "primary.host") was last set and
+// every earlier definition it overrode. The elements of a List share the Provenance of the List.
+// Will respond with an error if the path cannot be resolved
+func (section *Section) Provenance(path string) (Provenance, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return Provenance{}, err
+	}
+	if _, err = section.Resolve(path); err != nil {
+		return Provenance{}, err
+	}
+
+	// Elements of a List are defined with the List
+	for len(segments) > 0 && segments[len(segments)-1].isIndex {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) == 0 || segments[len(segments)-1].isParent {
+		return Provenance{}, fmt.Errorf("'%s' does not refer to a value of a section", path)
+	}
+
+	owner, key, err := section.resolveOwner(formatPath(segments))
+	if err != nil {
+		return Provenance{}, err
+	}
+
+	provenance := Provenance{Path: absolutePath(section, formatPath(segments))}
+	definitions := owner.definitions[key]
+	if len(definitions) > 0 {
+		provenance.Position = definitions[len(definitions)-1]
+		for i := len(definitions) - 2; i >= 0; i-- {
+			provenance.Overridden = append(provenance.Overridden, definitions[i])
+		}
+	}
+	return provenance, nil
+}
+
+// resolveOwner will respond with the Section which holds the value at path and the key of the value
+// in that Section, the key is empty when the last segment of path is not a key
+func (section *Section) resolveOwner(path string) (*Section, string, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	last := segments[len(segments)-1]
+	if last.isIndex || last.isParent {
+		return nil, "", nil
+	}
+
+	container, err := section.resolveContainer(segments)
+	if err != nil {
+		return nil, "", err
+	}
+	owner := container.(*Section)
+	if _, err = owner.Get(last.key); err != nil {
+		return nil, "", err
+	}
+	return owner, last.key, nil
+}
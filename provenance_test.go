@@ -0,0 +1,158 @@
+package forge_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func writeConfig(t *testing.T, filename string, data string) {
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tempDir will create a temporary directory, the returned func removes it
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "forge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// setenv will set the environment variable name to value, the returned func restores it
+func setenv(t *testing.T, name string, value string) func() {
+	previous, ok := os.LookupEnv(name)
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if ok {
+			os.Setenv(name, previous)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	mainFile := filepath.Join(dir, "main.cfg")
+	overrideFile := filepath.Join(dir, "override.cfg")
+	writeConfig(t, mainFile, `primary {
+  host = "localhost";
+  hosts = ["a", "b"];
+}
+primary.host = "main";
+include "`+overrideFile+`";
+`)
+	writeConfig(t, overrideFile, "\nprimary.host = \"override\";\n")
+
+	settings, err := forge.ParseFile(mainFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provenance, err := settings.Provenance("primary.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenance.Path != "primary.host" {
+		t.Errorf("expected the path 'primary.host' got '%s'", provenance.Path)
+	}
+	expected := forge.Position{Filename: overrideFile, Line: 2, Column: 1}
+	if provenance.Position != expected {
+		t.Errorf("expected %s got %s", expected, provenance.Position)
+	}
+	overridden := []forge.Position{
+		{Filename: mainFile, Line: 5, Column: 1},
+		{Filename: mainFile, Line: 2, Column: 3},
+	}
+	if len(provenance.Overridden) != len(overridden) {
+		t.Fatalf("expected %v got %v", overridden, provenance.Overridden)
+	}
+	for i := range overridden {
+		if provenance.Overridden[i] != overridden[i] {
+			t.Errorf("expected %s got %s", overridden[i], provenance.Overridden[i])
+		}
+	}
+
+	// Elements of a list share the provenance of the list
+	provenance, err = settings.Provenance("primary.hosts[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenance.Path != "primary.hosts" || provenance.Position.Line != 3 {
+		t.Errorf("expected the provenance of 'primary.hosts' got %#v", provenance)
+	}
+
+	// Values set with the api do not have a valid position
+	primary, err := settings.GetSection("primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary.SetString("host", "api")
+	provenance, err = settings.Provenance("primary.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenance.Position.IsValid() || len(provenance.Overridden) != 3 {
+		t.Errorf("expected an invalid position overriding 3 definitions got %#v", provenance)
+	}
+
+	// Consecutive values set with the api are recorded once
+	for i := 0; i < 10; i++ {
+		primary.SetString("host", "api")
+	}
+	provenance, err = settings.Provenance("primary.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provenance.Overridden) != 3 {
+		t.Errorf("expected repeated api writes not to add definitions got %#v", provenance)
+	}
+
+	if _, err = settings.Provenance("primary.missing"); err == nil {
+		t.Error("expected an error for a missing value")
+	}
+}
+
+func TestProvenanceMerge(t *testing.T) {
+	t.Parallel()
+
+	target, err := forge.ParseString("host = \"target\";\nfinal = 1;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := forge.ParseString("\nhost = \"source\";\nfinal = 2;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = target.MergeWithOptions(source, forge.MergeOptions{Final: []string{"final"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	provenance, err := target.Provenance("host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenance.Position.Line != 2 || len(provenance.Overridden) != 1 || provenance.Overridden[0].Line != 1 {
+		t.Errorf("expected line 2 overriding line 1 got %#v", provenance)
+	}
+
+	// Final values keep their provenance
+	provenance, err = target.Provenance("final")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenance.Position.Line != 2 || len(provenance.Overridden) != 0 {
+		t.Errorf("expected line 2 without overrides got %#v", provenance)
+	}
+}
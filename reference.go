@@ -1,11 +1,14 @@
 package forge
 
-import "errors"
+import (
+	"errors"
+)
 
 // Reference struct used for holding data neede for Reference data type
 type Reference struct {
-	section *Section
-	name    string
+	section  *Section
+	name     string
+	position Position
 }
 
 // NewReference will create and initialize a new Reference value
@@ -16,12 +19,79 @@ func NewReference(name string, section *Section) *Reference {
 	}
 }
 
-func (reference *Reference) resolve() Value {
-	value, err := reference.section.Resolve(reference.name)
-	if err != nil {
-		value = NewNull()
+// Resolve will follow this Reference, and any References it refers to, until it finds a
+// non-reference value. Will respond with a *ReferenceError if a value does not exist or if
+// the References form a cycle
+func (reference *Reference) Resolve() (Value, error) {
+	value, _, err := reference.resolve()
+	return value, err
+}
+
+// resolve will follow the References like `Resolve` and will also return the
+// last Reference followed, the one which refers to the returned value
+func (reference *Reference) resolve() (Value, *Reference, error) {
+	var seen []*Reference
+	current := reference
+	for {
+		for i, previous := range seen {
+			if previous == current {
+				return nil, nil, &ReferenceError{
+					Name:      reference.name,
+					Position:  reference.position,
+					Cycle:     referenceCycle(seen[i:]),
+					reference: reference,
+				}
+			}
+		}
+		seen = append(seen, current)
+
+		value, err := current.section.Resolve(current.name)
+		if err != nil {
+			return nil, nil, &ReferenceError{
+				Name:      current.name,
+				Position:  current.position,
+				Err:       err,
+				reference: current,
+			}
+		}
+
+		next, ok := value.(*Reference)
+		if ok == false {
+			return value, current, nil
+		}
+		current = next
+	}
+}
+
+// dereference will resolve the value if it is a Reference, otherwise the value is returned as is
+func dereference(value Value) (Value, error) {
+	if reference, ok := value.(*Reference); ok {
+		return reference.Resolve()
+	}
+	return value, nil
+}
+
+// referenceCycle will return the path of every value in the cycle, where every Reference
+// in the cycle refers to the next one and the last refers back to the first
+func referenceCycle(cycle []*Reference) []string {
+	paths := make([]string, 0, len(cycle)+1)
+	last := cycle[len(cycle)-1]
+	paths = append(paths, absolutePath(last.section, last.name))
+	for _, reference := range cycle[:len(cycle)-1] {
+		paths = append(paths, absolutePath(reference.section, reference.name))
 	}
-	return value
+	return append(paths, paths[0])
+}
+
+// GetName will return the name of the value this Reference refers to
+func (reference *Reference) GetName() string {
+	return reference.name
+}
+
+// GetPosition will return where this Reference was defined, the Position is
+// only valid for References created by the parser
+func (reference *Reference) GetPosition() Position {
+	return reference.position
 }
 
 // GetType will simply return back REFERENCE
@@ -30,8 +100,28 @@ func (reference *Reference) GetType() ValueType {
 }
 
 // GetValue will resolve and return the value from the underlying reference
+// will return nil if the reference cannot be resolved, use `Resolve` to get the error
 func (reference *Reference) GetValue() interface{} {
-	return reference.resolve().GetValue()
+	value, err := reference.Resolve()
+	if err != nil {
+		return nil
+	}
+	return value.GetValue()
+}
+
+// Clone will return a new Reference with the same name which is resolved from the same Section
+func (reference *Reference) Clone() Value {
+	return newCloner().clone(reference, nil)
+}
+
+// Equal will return true if other is a Reference with the same name,
+// References are compared without being resolved
+func (reference *Reference) Equal(other Value) bool {
+	otherReference, ok := other.(*Reference)
+	if ok == false {
+		return false
+	}
+	return reference.name == otherReference.name
 }
 
 // UpdateValue will simply throw an error since it is not allowed for References
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"io"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/brettlangdon/forge/token"
 )
@@ -11,13 +13,17 @@ import (
 var eof = rune(0)
 
 func isLetter(ch rune) bool {
-	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || (ch >= utf8.RuneSelf && unicode.IsLetter(ch))
 }
 
 func isDigit(ch rune) bool {
 	return ('0' <= ch && ch <= '9')
 }
 
+func isIdentifierCharacter(ch rune) bool {
+	return isLetter(ch) || isDigit(ch) || ch == '_' || ch == '-'
+}
+
 func isNonNewlineWhitespace(ch rune) bool {
 	return (ch == ' ' || ch == '\t' || ch == '\r')
 }
@@ -85,7 +91,7 @@ func (scanner *Scanner) parseIdentifier() {
 	scanner.curTok.Literal = string(scanner.curCh)
 	for {
 		scanner.readRune()
-		if !isLetter(scanner.curCh) && !isDigit(scanner.curCh) && scanner.curCh != '_' {
+		if !isIdentifierCharacter(scanner.curCh) {
 			break
 		}
 		scanner.curTok.Literal += string(scanner.curCh)
@@ -1,6 +1,7 @@
 package forge
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,54 +9,135 @@ import (
 	"strings"
 )
 
-var (
-	// ErrNotExists represents a nonexistent value error
-	ErrNotExists = errors.New("value does not exist")
-)
+// KeyComments holds the comments which document a single key in a Section
+//
+// Leading comments are the comments on the lines directly before the key
+// and Trailing is the comment at the end of the line the key is defined on
+type KeyComments struct {
+	Leading  []string
+	Trailing string
+}
 
 // Section struct holds a map of values
 type Section struct {
-	comments []string
-	includes []string
-	parent   *Section
-	values   map[string]Value
+	comments    []string
+	definitions map[string][]Position
+	frozen      bool
+	includes    []string
+	keyComments map[string]*KeyComments
+	order       []string
+	parent      *Section
+	strict      bool
+	values      map[string]Value
 }
 
 // NewSection will create and initialize a new Section
 func NewSection() *Section {
 	return &Section{
-		comments: make([]string, 0),
-		includes: make([]string, 0),
-		values:   make(map[string]Value),
+		comments:    make([]string, 0),
+		definitions: make(map[string][]Position),
+		includes:    make([]string, 0),
+		keyComments: make(map[string]*KeyComments),
+		order:       make([]string, 0),
+		values:      make(map[string]Value),
 	}
 }
 
 func newChildSection(parent *Section) *Section {
 	return &Section{
-		comments: make([]string, 0),
-		includes: make([]string, 0),
-		parent:   parent,
-		values:   make(map[string]Value),
+		comments:    make([]string, 0),
+		definitions: make(map[string][]Position),
+		includes:    make([]string, 0),
+		keyComments: make(map[string]*KeyComments),
+		order:       make([]string, 0),
+		parent:      parent,
+		values:      make(map[string]Value),
 	}
 }
 
 // AddComment will append a new comment into the section
-func (section *Section) AddComment(comment string) {
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) AddComment(comment string) error {
+	if err := section.frozenError(""); err != nil {
+		return err
+	}
 	section.comments = append(section.comments, comment)
+	return nil
+}
+
+func (section *Section) getKeyComments(name string) *KeyComments {
+	comments, ok := section.keyComments[name]
+	if ok == false {
+		comments = &KeyComments{
+			Leading: make([]string, 0),
+		}
+		section.keyComments[name] = comments
+	}
+	return comments
+}
+
+// AddLeadingComment will append a new comment to the comments which come before the key name
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) AddLeadingComment(name string, comment string) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
+	comments := section.getKeyComments(name)
+	comments.Leading = append(comments.Leading, comment)
+	return nil
+}
+
+// SetTrailingComment will set the end of line comment for the key name
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetTrailingComment(name string, comment string) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
+	section.getKeyComments(name).Trailing = comment
+	return nil
 }
 
 // AddInclude will append a new filename into the section
-func (section *Section) AddInclude(filename string) {
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) AddInclude(filename string) error {
+	if err := section.frozenError(""); err != nil {
+		return err
+	}
 	section.includes = append(section.includes, filename)
+	return nil
 }
 
-// GetComments will return all the comments were defined for this Section
+// GetComments will return all the comments were defined for this Section,
+// a copy of them if this Section is frozen
 func (section *Section) GetComments() []string {
+	if section.frozen {
+		return append(make([]string, 0, len(section.comments)), section.comments...)
+	}
 	return section.comments
 }
 
-// GetIncludes will return the filenames of all the includes were parsed for this Section
+// GetKeyComments will return the leading and trailing comments associated with the key name
+// will respond with an error if the key does not exist
+func (section *Section) GetKeyComments(name string) (KeyComments, error) {
+	if _, err := section.Get(name); err != nil {
+		return KeyComments{}, err
+	}
+
+	comments, ok := section.keyComments[name]
+	if ok == false {
+		return KeyComments{Leading: make([]string, 0)}, nil
+	}
+	leading := make([]string, len(comments.Leading))
+	copy(leading, comments.Leading)
+	return KeyComments{Leading: leading, Trailing: comments.Trailing}, nil
+}
+
+// GetIncludes will return the filenames of all the includes were parsed for this Section,
+// a copy of them if this Section is frozen
 func (section *Section) GetIncludes() []string {
+	if section.frozen {
+		return append(make([]string, 0, len(section.includes)), section.includes...)
+	}
 	return section.includes
 }
 
@@ -64,16 +146,35 @@ func (section *Section) GetType() ValueType {
 	return SECTION
 }
 
-// GetValue retrieves the raw underlying value stored in this Section
+// GetValue retrieves the raw underlying value stored in this Section,
+// a copy of it if this Section is frozen
 func (section *Section) GetValue() interface{} {
+	if section.frozen {
+		values := make(map[string]Value, len(section.values))
+		for key, value := range section.values {
+			values[key] = value
+		}
+		return values
+	}
 	return section.values
 }
 
 // UpdateValue updates the raw underlying value stored in this Section
 func (section *Section) UpdateValue(value interface{}) error {
+	if err := section.frozenError(""); err != nil {
+		return err
+	}
+
 	switch value.(type) {
 	case map[string]Value:
 		section.values = value.(map[string]Value)
+		for _, value := range section.values {
+			if list, ok := value.(*List); ok {
+				list.setOwner(section)
+			}
+		}
+		// A map has no order, so fall back to sorted keys
+		section.order = section.Keys()
 		return nil
 	}
 
@@ -81,123 +182,444 @@ func (section *Section) UpdateValue(value interface{}) error {
 	return errors.New(msg)
 }
 
+// Clone will return a deep copy of this Section and every value below it. The copy has no
+// parent, the parent of every Section below it is its copied parent and References below it
+// are resolved from the copied Sections
+func (section *Section) Clone() Value {
+	return newCloner().clone(section, nil)
+}
+
+// Equal will return true if other is a Section with the same keys where every value is Equal,
+// comments, includes and the order of the keys are not compared
+func (section *Section) Equal(other Value) bool {
+	otherSection, ok := other.(*Section)
+	if ok == false || len(section.values) != len(otherSection.values) {
+		return false
+	}
+	for key, value := range section.values {
+		otherValue, ok := otherSection.values[key]
+		if ok == false || value.Equal(otherValue) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// cloner deep copies values, remembering the copy of every Section so the parents of
+// Sections and the Sections References are resolved from can refer to the copies
+type cloner struct {
+	sections map[*Section]*Section
+}
+
+func newCloner() *cloner {
+	return &cloner{
+		sections: make(map[*Section]*Section),
+	}
+}
+
+// clone will deep copy value, a copied Section has the provided parent
+func (c *cloner) clone(value Value, parent *Section) Value {
+	switch value := value.(type) {
+	case *Section:
+		copied := newChildSection(parent)
+		c.sections[value] = copied
+		copied.comments = append(copied.comments, value.comments...)
+		copied.includes = append(copied.includes, value.includes...)
+		copied.strict = value.strict
+		for key, comments := range value.keyComments {
+			copied.keyComments[key] = &KeyComments{
+				Leading:  append(make([]string, 0), comments.Leading...),
+				Trailing: comments.Trailing,
+			}
+		}
+		for key, definitions := range value.definitions {
+			copied.definitions[key] = append(make([]Position, 0), definitions...)
+		}
+		for _, key := range value.order {
+			copied.setValue(key, c.clone(value.values[key], copied))
+		}
+		return copied
+	case *List:
+		copied := NewList()
+		for _, element := range value.values {
+			copied.Append(c.clone(element, parent))
+		}
+		return copied
+	case *Reference:
+		copied := NewReference(value.name, value.section)
+		copied.position = value.position
+		if section, ok := c.sections[value.section]; ok {
+			copied.section = section
+		}
+		return copied
+	}
+	return value.Clone()
+}
+
+// SetStrictTypes will enable or disable strict type conversion for the getters of this Section
+// and every Section below it, see `forge.SetStrictTypes`.
+// Will respond with a *FrozenError if the section is frozen
+func (section *Section) SetStrictTypes(strict bool) error {
+	if err := section.frozenError(""); err != nil {
+		return err
+	}
+	section.strict = strict
+	return nil
+}
+
+// Freeze will make this Section and every Section, List and Primative below it read only, every
+// call which would change them responds with a *FrozenError instead and the getters of the
+// underlying values respond with copies. Use `CreateSection` to add a Section to a Section
+// which may be frozen. A frozen Section is safe to read from multiple goroutines, use `Clone`
+// to get a copy which can be changed again
+func (section *Section) Freeze() {
+	freeze(section)
+}
+
+// freeze will mark value and every value below it as frozen
+func freeze(value Value) {
+	switch value := value.(type) {
+	case *Section:
+		// DEV: Everything below a frozen Section is already frozen
+		if value.frozen {
+			return
+		}
+		value.frozen = true
+		for _, child := range value.values {
+			freeze(child)
+		}
+	case *List:
+		value.frozen = true
+		for _, element := range value.values {
+			freeze(element)
+		}
+	case *Primative:
+		value.frozen = true
+	}
+}
+
+// IsFrozen will return true if this Section was frozen with `Freeze`
+func (section *Section) IsFrozen() bool {
+	return section.frozen
+}
+
+// frozenError will respond with a *FrozenError for the value stored under name if this
+// Section is frozen, an empty name refers to the Section itself
+func (section *Section) frozenError(name string) error {
+	if section.frozen == false {
+		return nil
+	}
+	if name == "" {
+		return &FrozenError{Path: formatPath(sectionPath(section))}
+	}
+	return &FrozenError{Path: absolutePath(section, EscapeKey(name))}
+}
+
+// strictTypes will return true if strict type conversion is enabled globally or for this
+// Section or any Section above it
+func (section *Section) strictTypes() bool {
+	if StrictTypes() {
+		return true
+	}
+	for current := section; current != nil; current = current.parent {
+		if current.strict {
+			return true
+		}
+	}
+	return false
+}
+
+// withPath will prepend the path of the value at name in this Section to the path of
+// an error from converting that value
+func (section *Section) withPath(err error, name string) error {
+	if err == nil {
+		return nil
+	}
+	return prefixErrorPath(err, absolutePath(section, name))
+}
+
 // AddSection adds a new child section to this Section with the provided name
+//
+// Deprecated: AddSection cannot respond with an error, use `CreateSection` which responds
+// with a *FrozenError if the section is frozen
 func (section *Section) AddSection(name string) *Section {
+	childSection, err := section.CreateSection(name)
+	if err != nil {
+		panic(err)
+	}
+	return childSection
+}
+
+// CreateSection adds a new child section to this Section with the provided name like `AddSection`
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) CreateSection(name string) (*Section, error) {
+	if err := section.frozenError(name); err != nil {
+		return nil, err
+	}
+	childSection := section.addSection(name)
+	section.define(name, Position{})
+	return childSection, nil
+}
+
+// addSection will add a new child section without recording where it was defined
+func (section *Section) addSection(name string) *Section {
 	childSection := newChildSection(section)
-	section.values[name] = childSection
+	section.setValue(name, childSection)
 	return childSection
 }
 
+// define will record that the value for name was set at position, the position
+// is not valid for values set with the Section api rather than parsed.
+// Consecutive values set with the Section api are recorded once
+func (section *Section) define(name string, position Position) {
+	definitions := section.definitions[name]
+	if position.IsValid() == false && len(definitions) > 0 && definitions[len(definitions)-1].IsValid() == false {
+		return
+	}
+	section.definitions[name] = append(definitions, position)
+}
+
 // Exists returns true when a value stored under the key exists
 func (section *Section) Exists(name string) bool {
-	_, err := section.Get(name)
-	return err == nil
+	_, ok := section.values[name]
+	return ok
 }
 
 // Get the value (Primative or Section) stored under the name
-// will respond with an error if the value does not exist
+// will respond with a *NotFoundError if the value does not exist
 func (section *Section) Get(name string) (Value, error) {
 	value, ok := section.values[name]
-	var err error
 	if ok == false {
-		err = ErrNotExists
+		path := sectionPath(section)
+		return nil, newNotFoundError(section, formatPath(append(path, pathSegment{key: name})), formatPath(path), name)
 	}
-	return value, err
+	return value, nil
+}
+
+// getDereferenced will get the value stored under name, following it if it is a Reference
+func (section *Section) getDereferenced(name string) (Value, error) {
+	value, err := section.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return dereference(value)
 }
 
 // GetBoolean will try to get the value stored under name as a bool
 // will respond with an error if the value does not exist or cannot be converted to a bool
 func (section *Section) GetBoolean(name string) (bool, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return false, err
 	}
-
-	switch value.(type) {
-	case *Primative:
-		return value.(*Primative).AsBoolean()
-	case *Section:
-		return true, nil
-	}
-
-	return false, errors.New("could not convert unknown value to boolean")
+	converted, err := toBoolean(value, section.strictTypes())
+	return converted, section.withPath(err, EscapeKey(name))
 }
 
 // GetFloat will try to get the value stored under name as a float64
 // will respond with an error if the value does not exist or cannot be converted to a float64
 func (section *Section) GetFloat(name string) (float64, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return float64(0), err
 	}
-
-	switch value.(type) {
-	case *Primative:
-		return value.(*Primative).AsFloat()
-	}
-
-	return float64(0), errors.New("could not convert non-primative value to float")
+	converted, err := toFloat(value, section.strictTypes())
+	return converted, section.withPath(err, EscapeKey(name))
 }
 
 // GetInteger will try to get the value stored under name as a int64
 // will respond with an error if the value does not exist or cannot be converted to a int64
 func (section *Section) GetInteger(name string) (int64, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return int64(0), err
 	}
-
-	switch value.(type) {
-	case *Primative:
-		return value.(*Primative).AsInteger()
-	}
-
-	return int64(0), errors.New("could not convert non-primative value to integer")
+	converted, err := toInteger(value, section.strictTypes())
+	return converted, section.withPath(err, EscapeKey(name))
 }
 
 // GetList will try to get the value stored under name as a List
 // will respond with an error if the value does not exist or is not a List
 func (section *Section) GetList(name string) (*List, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return nil, err
 	}
-
-	if value.GetType() == LIST {
-		return value.(*List), nil
-	}
-
-	return nil, errors.New("could not fetch value as list")
+	list, err := toList(value)
+	return list, section.withPath(err, EscapeKey(name))
 }
 
 // GetSection will try to get the value stored under name as a Section
 // will respond with an error if the value does not exist or is not a Section
 func (section *Section) GetSection(name string) (*Section, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return nil, err
 	}
-
-	if value.GetType() == SECTION {
-		return value.(*Section), nil
-	}
-	return nil, errors.New("could not fetch value as section")
+	child, err := toSection(value)
+	return child, section.withPath(err, EscapeKey(name))
 }
 
 // GetString will try to get the value stored under name as a string
 // will respond with an error if the value does not exist or cannot be converted to a string
 func (section *Section) GetString(name string) (string, error) {
-	value, err := section.Get(name)
+	value, err := section.getDereferenced(name)
 	if err != nil {
 		return "", err
 	}
+	converted, err := toString(value, section.strictTypes())
+	return converted, section.withPath(err, EscapeKey(name))
+}
+
+// GetBooleanSlice will try to get the List stored under name as a []bool, see `List.Booleans`
+func (section *Section) GetBooleanSlice(name string) ([]bool, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.booleans(false, section.strictTypes())
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetStrictBooleanSlice will try to get the List stored under name as a []bool
+// will respond with an error if any element is not a BOOLEAN, see `List.StrictBooleans`
+func (section *Section) GetStrictBooleanSlice(name string) ([]bool, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.StrictBooleans()
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetFloatSlice will try to get the List stored under name as a []float64, see `List.Floats`
+func (section *Section) GetFloatSlice(name string) ([]float64, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.floats(false, section.strictTypes())
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetStrictFloatSlice will try to get the List stored under name as a []float64
+// will respond with an error if any element is not a FLOAT, see `List.StrictFloats`
+func (section *Section) GetStrictFloatSlice(name string) ([]float64, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.StrictFloats()
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetIntegerSlice will try to get the List stored under name as a []int64, see `List.Integers`
+func (section *Section) GetIntegerSlice(name string) ([]int64, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.integers(false, section.strictTypes())
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetStrictIntegerSlice will try to get the List stored under name as a []int64
+// will respond with an error if any element is not an INTEGER, see `List.StrictIntegers`
+func (section *Section) GetStrictIntegerSlice(name string) ([]int64, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.StrictIntegers()
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetSectionSlice will try to get the List stored under name as a []*Section
+// will respond with an error if any element is not a SECTION, see `List.Sections`
+func (section *Section) GetSectionSlice(name string) ([]*Section, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := list.Sections()
+	return sections, section.withPath(err, EscapeKey(name))
+}
 
+// GetStringSlice will try to get the List stored under name as a []string, see `List.Strings`
+func (section *Section) GetStringSlice(name string) ([]string, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.strings(false, section.strictTypes())
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+// GetStrictStringSlice will try to get the List stored under name as a []string
+// will respond with an error if any element is not a STRING, see `List.StrictStrings`
+func (section *Section) GetStrictStringSlice(name string) ([]string, error) {
+	list, err := section.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list.StrictStrings()
+	return values, section.withPath(err, EscapeKey(name))
+}
+
+func toBoolean(value Value, strict bool) (bool, error) {
 	switch value.(type) {
 	case *Primative:
-		return value.(*Primative).AsString()
+		return value.(*Primative).asBoolean(strict)
+	case *Section:
+		if strict {
+			return false, &TypeError{Want: BOOLEAN, Got: SECTION}
+		}
+		return true, nil
 	}
 
-	return "", errors.New("could not convert non-primative value to string")
+	return false, &TypeError{Want: BOOLEAN, Got: value.GetType()}
+}
+
+func toFloat(value Value, strict bool) (float64, error) {
+	switch value.(type) {
+	case *Primative:
+		return value.(*Primative).asFloat(strict)
+	}
+
+	return float64(0), &TypeError{Want: FLOAT, Got: value.GetType()}
+}
+
+func toInteger(value Value, strict bool) (int64, error) {
+	switch value.(type) {
+	case *Primative:
+		return value.(*Primative).asInteger(strict)
+	}
+
+	return int64(0), &TypeError{Want: INTEGER, Got: value.GetType()}
+}
+
+func toList(value Value) (*List, error) {
+	if value.GetType() == LIST {
+		return value.(*List), nil
+	}
+
+	return nil, &TypeError{Want: LIST, Got: value.GetType()}
+}
+
+func toSection(value Value) (*Section, error) {
+	if value.GetType() == SECTION {
+		return value.(*Section), nil
+	}
+	return nil, &TypeError{Want: SECTION, Got: value.GetType()}
+}
+
+func toString(value Value, strict bool) (string, error) {
+	switch value.(type) {
+	case *Primative:
+		return value.(*Primative).asString(strict)
+	}
+
+	return "", &TypeError{Want: STRING, Got: value.GetType()}
 }
 
 // GetParent will get the parent section associated with this Section or nil
@@ -211,7 +633,8 @@ func (section *Section) HasParent() bool {
 	return section.parent != nil
 }
 
-// Keys will return back a list of all setting names in this Section
+// Keys will return back a list of all setting names in this Section sorted alphabetically,
+// use `OrderedKeys` for the order they were declared in
 func (section *Section) Keys() []string {
 	var keys []string
 	for key := range section.values {
@@ -222,134 +645,663 @@ func (section *Section) Keys() []string {
 	return keys
 }
 
-// Set will set a value (Primative or Section) to the provided name
-func (section *Section) Set(name string, value Value) {
+// OrderedKeys will return back a list of all setting names in this Section in the
+// order they were first set (e.g. the order they were declared in a config file)
+func (section *Section) OrderedKeys() []string {
+	return append(make([]string, 0, len(section.order)), section.order...)
+}
+
+// setValue will store value under name, remembering the order new names were added in
+func (section *Section) setValue(name string, value Value) {
+	if _, ok := section.values[name]; ok == false {
+		section.order = append(section.order, name)
+	}
 	section.values[name] = value
+	if list, ok := value.(*List); ok {
+		list.setOwner(section)
+	}
 }
 
-// SetBoolean will set the value for name as a bool
-func (section *Section) SetBoolean(name string, value bool) {
-	current, err := section.Get(name)
+// Set will set a value (Primative or Section) to the provided name
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) Set(name string, value Value) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
+	section.setValue(name, value)
+	section.define(name, Position{})
+	return nil
+}
 
+// setKeys will set value for the last of the keys in the nested Sections named by the
+// other keys, creating any Sections which do not exist yet
+func (section *Section) setKeys(keys []string, value Value) error {
+	current := section
+	for i, key := range keys {
+		if key == "" {
+			return fmt.Errorf("cannot set '%s', it contains an empty key", JoinPath(keys...))
+		}
+		if i == len(keys)-1 {
+			break
+		}
+
+		next, ok := current.values[key]
+		if ok == false {
+			var err error
+			if current, err = current.CreateSection(key); err != nil {
+				return err
+			}
+			continue
+		}
+		if next.GetType() != SECTION {
+			return &TypeError{
+				Path: absolutePath(section, JoinPath(keys[:i+1]...)),
+				Want: SECTION,
+				Got:  next.GetType(),
+			}
+		}
+		current = next.(*Section)
+	}
+	return current.Set(keys[len(keys)-1], value)
+}
+
+// SetBoolean will set the value for name as a bool
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetBoolean(name string, value bool) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
 	// Exists just update the value/type
-	if err == nil {
-		current.UpdateValue(value)
+	if current, ok := section.values[name]; ok {
+		if err := current.UpdateValue(value); err != nil {
+			return section.withPath(err, EscapeKey(name))
+		}
 	} else {
-		section.values[name] = NewBoolean(value)
+		section.setValue(name, NewBoolean(value))
 	}
+	section.define(name, Position{})
+	return nil
 }
 
 // SetFloat will set the value for name as a float64
-func (section *Section) SetFloat(name string, value float64) {
-	current, err := section.Get(name)
-
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetFloat(name string, value float64) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
 	// Exists just update the value/type
-	if err == nil {
-		current.UpdateValue(value)
+	if current, ok := section.values[name]; ok {
+		if err := current.UpdateValue(value); err != nil {
+			return section.withPath(err, EscapeKey(name))
+		}
 	} else {
-		section.values[name] = NewFloat(value)
+		section.setValue(name, NewFloat(value))
 	}
+	section.define(name, Position{})
+	return nil
 }
 
 // SetInteger will set the value for name as a int64
-func (section *Section) SetInteger(name string, value int64) {
-	current, err := section.Get(name)
-
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetInteger(name string, value int64) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
 	// Exists just update the value/type
-	if err == nil {
-		current.UpdateValue(value)
+	if current, ok := section.values[name]; ok {
+		if err := current.UpdateValue(value); err != nil {
+			return section.withPath(err, EscapeKey(name))
+		}
 	} else {
-		section.values[name] = NewInteger(value)
+		section.setValue(name, NewInteger(value))
 	}
+	section.define(name, Position{})
+	return nil
 }
 
 // SetNull will set the value for name as nil
-func (section *Section) SetNull(name string) {
-	current, err := section.Get(name)
-
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetNull(name string) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
 	// Already is a Null, nothing to do
-	if err == nil && current.GetType() == NULL {
-		return
+	if current, ok := section.values[name]; ok && current.GetType() == NULL {
+		section.define(name, Position{})
+		return nil
 	}
-	section.Set(name, NewNull())
+	return section.Set(name, NewNull())
 }
 
 // SetString will set the value for name as a string
-func (section *Section) SetString(name string, value string) {
-	current, err := section.Get(name)
-
+// will respond with a *FrozenError if the section is frozen
+func (section *Section) SetString(name string, value string) error {
+	if err := section.frozenError(name); err != nil {
+		return err
+	}
 	// Exists just update the value/type
-	if err == nil {
-		current.UpdateValue(value)
+	if current, ok := section.values[name]; ok {
+		if err := current.UpdateValue(value); err != nil {
+			return section.withPath(err, EscapeKey(name))
+		}
+		section.define(name, Position{})
+		return nil
+	}
+	return section.Set(name, NewString(value))
+}
+
+// deleteValue will remove the value stored under name along with its comments
+func (section *Section) deleteValue(name string) {
+	delete(section.values, name)
+	delete(section.keyComments, name)
+	delete(section.definitions, name)
+	for i, key := range section.order {
+		if key == name {
+			section.order = append(section.order[:i], section.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// resolveContainer will resolve every segment except the last, responding with the
+// Section or List the last segment refers into
+func (section *Section) resolveContainer(segments []pathSegment) (Value, error) {
+	last := segments[len(segments)-1]
+	if last.isParent {
+		return nil, fmt.Errorf("'%s' does not refer to a value of a section or list", formatPath(segments))
+	}
+
+	var container Value = section
+	if len(segments) > 1 {
+		var err error
+		container, err = section.Resolve(formatPath(segments[:len(segments)-1]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wantType := SECTION
+	if last.isIndex {
+		wantType = LIST
+	}
+	if container.GetType() != wantType {
+		return nil, &TypeError{
+			Path: absolutePath(section, formatPath(segments[:len(segments)-1])),
+			Want: wantType,
+			Got:  container.GetType(),
+		}
+	}
+	return container, nil
+}
+
+// Delete will remove the value at path (e.g. "primary.sub.key" or "primary.hosts[0]"),
+// removing an element of a List shifts the elements after it down by one.
+// Will respond with an error if the path cannot be resolved
+func (section *Section) Delete(path string) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	container, err := section.resolveContainer(segments)
+	if err != nil {
+		return err
+	}
+
+	last := segments[len(segments)-1]
+	if last.isIndex {
+		err = container.(*List).Remove(last.index)
+		return section.withPath(err, formatPath(segments[:len(segments)-1]))
+	}
+
+	parent := container.(*Section)
+	value, err := parent.Get(last.key)
+	if err != nil {
+		return err
+	}
+	if err = parent.frozenError(last.key); err != nil {
+		return err
+	}
+	parent.deleteValue(last.key)
+	if child, ok := value.(*Section); ok && child.parent == parent && child.frozen == false {
+		child.parent = nil
+	}
+	return nil
+}
+
+// Rename will rename the value stored under oldName in this Section to newName, the value
+// keeps its position in `OrderedKeys` and its comments.
+// Will respond with an error if oldName does not exist or newName already exists.
+// References to the old name are not updated
+func (section *Section) Rename(oldName string, newName string) error {
+	value, err := section.Get(oldName)
+	if err != nil {
+		return err
+	}
+	if oldName == newName {
+		return nil
+	}
+	if err = section.frozenError(oldName); err != nil {
+		return err
+	}
+	if section.Exists(newName) {
+		return fmt.Errorf("cannot rename '%s', value '%s' already exists", oldName, absolutePath(section, EscapeKey(newName)))
+	}
+
+	delete(section.values, oldName)
+	section.values[newName] = value
+	for i, key := range section.order {
+		if key == oldName {
+			section.order[i] = newName
+			break
+		}
+	}
+	if comments, ok := section.keyComments[oldName]; ok {
+		delete(section.keyComments, oldName)
+		section.keyComments[newName] = comments
+	}
+	if definitions, ok := section.definitions[oldName]; ok {
+		delete(section.definitions, oldName)
+		section.definitions[newName] = definitions
+	}
+	return nil
+}
+
+// Move will move the value at srcPath (e.g. "primary.hosts" or "primary.hosts[0]") to dstPath
+// (e.g. "secondary.hosts"), creating any Sections in dstPath which do not exist yet, the value
+// keeps its comments. Will respond with an error if srcPath cannot be resolved, dstPath already
+// exists, contains an empty key, goes through a non-section value or a Section would be moved into itself.
+// References to the old path are not updated
+func (section *Section) Move(srcPath string, dstPath string) error {
+	value, err := section.Resolve(srcPath)
+	if err != nil {
+		return err
+	}
+
+	segments, err := parsePath(dstPath)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment.key == "" && segment.isIndex == false && segment.isParent == false {
+			return fmt.Errorf("cannot move '%s', '%s' contains an empty key", srcPath, dstPath)
+		}
+	}
+	last := segments[len(segments)-1]
+	if last.isIndex || last.isParent {
+		return fmt.Errorf("cannot move '%s', '%s' does not refer to a key of a section", srcPath, dstPath)
+	}
+
+	// Find the destination section, creating any missing sections after checking the whole path
+	current := section
+	var missing []pathSegment
+	for i, segment := range segments[:len(segments)-1] {
+		if segment.isParent {
+			if current.parent == nil {
+				return &NotFoundError{Path: absolutePath(section, dstPath), Missing: formatPath(segments[:i+1])}
+			}
+			current = current.parent
+			continue
+		}
+		if segment.isIndex {
+			return fmt.Errorf("cannot move '%s', '%s' does not refer to a key of a section", srcPath, dstPath)
+		}
+		next, ok := current.values[segment.key]
+		if ok == false {
+			missing = segments[i : len(segments)-1]
+			break
+		}
+		if next.GetType() != SECTION {
+			return &TypeError{
+				Path: absolutePath(section, formatPath(segments[:i+1])),
+				Want: SECTION,
+				Got:  next.GetType(),
+			}
+		}
+		current = next.(*Section)
+	}
+	if len(missing) == 0 && current.Exists(last.key) {
+		return fmt.Errorf("cannot move '%s', value '%s' already exists", srcPath, absolutePath(section, dstPath))
+	}
+	if moved, ok := value.(*Section); ok {
+		for ancestor := current; ancestor != nil; ancestor = ancestor.parent {
+			if ancestor == moved {
+				return fmt.Errorf("cannot move '%s' into itself", srcPath)
+			}
+		}
+		// The parent of the moved Section changes
+		if moved.frozen {
+			return &FrozenError{Path: absolutePath(section, srcPath)}
+		}
+	}
+	if len(missing) > 0 {
+		err = current.frozenError(missing[0].key)
 	} else {
-		section.Set(name, NewString(value))
+		err = current.frozenError(last.key)
+	}
+	if err != nil {
+		return err
 	}
+
+	// The moved value keeps its comments and the history of where it was defined
+	var definitions []Position
+	var comments *KeyComments
+	if owner, key, err := section.resolveOwner(srcPath); err == nil && key != "" {
+		definitions = owner.definitions[key]
+		comments = owner.keyComments[key]
+	}
+
+	if err = section.Delete(srcPath); err != nil {
+		return err
+	}
+	for _, segment := range missing {
+		// DEV: current was checked before deleting the source, so this cannot fail
+		current, _ = current.CreateSection(segment.key)
+	}
+	if moved, ok := value.(*Section); ok {
+		moved.parent = current
+	}
+	current.setValue(last.key, value)
+	if comments != nil {
+		current.keyComments[last.key] = comments
+	}
+	current.definitions[last.key] = definitions
+	current.define(last.key, Position{})
+	return nil
 }
 
 // Resolve will recursively try to fetch the provided value and will respond
-// with an error if the name does not exist or tries to be resolved through
-// a non-section value
+// with a *NotFoundError if the name does not exist, a *TypeError if it tries to
+// be resolved through a non-section value or an *IndexError if an index is out of range.
+// A *NotFoundError includes the part of the name which did resolve and suggestions for
+// the key which could not be found
+//
+// Elements of a List can be resolved with an index (e.g. `servers[0].host`) and
+// each period after the first at the start of the name steps up to the parent
+// Section (e.g. `..sibling.key`).
+// Periods, brackets and backslashes inside of a key name must be escaped with
+// a backslash, see `EscapeKey` and `JoinPath`
 func (section *Section) Resolve(name string) (Value, error) {
 	// Used only in error state return value
 	var value Value
 
-	parts := strings.Split(name, ".")
-	if len(parts) == 0 {
-		return value, errors.New("no name provided")
+	segments, err := parsePath(name)
+	if err != nil {
+		return value, err
 	}
 
 	var current Value
 	current = section
-	for _, part := range parts {
+	for i, segment := range segments {
+		if segment.isParent {
+			parent := current.(*Section).GetParent()
+			if parent == nil {
+				return value, &NotFoundError{Path: name, Missing: formatPath(segments[:i+1])}
+			}
+			current = parent
+			continue
+		}
+
+		if segment.isIndex {
+			if current.GetType() != LIST {
+				return value, &TypeError{
+					Path: absolutePath(section, formatPath(segments[:i])),
+					Want: LIST,
+					Got:  current.GetType(),
+				}
+			}
+
+			nextCurrent, err := current.(*List).Get(segment.index)
+			if err != nil {
+				return value, prefixErrorPath(err, absolutePath(section, formatPath(segments[:i])))
+			}
+			current = nextCurrent
+			continue
+		}
+
 		if current.GetType() != SECTION {
-			return value, errors.New("trying to resolve value from non-section")
+			return value, &TypeError{
+				Path: absolutePath(section, formatPath(segments[:i])),
+				Want: SECTION,
+				Got:  current.GetType(),
+			}
 		}
 
-		nextCurrent, err := current.(*Section).Get(part)
-		if err != nil {
-			return value, errors.New("could not find value in section")
+		nextCurrent, ok := current.(*Section).values[segment.key]
+		if ok == false {
+			resolved := formatPath(sectionPath(current.(*Section)))
+			return value, newNotFoundError(current.(*Section), absolutePath(section, name), resolved, segment.key)
 		}
 		current = nextCurrent
 	}
 	return current, nil
 }
 
-// Merge merges the given section to current section. Settings from source
-// section overwites the values in the current section
-func (section *Section) Merge(source *Section) error {
-	for _, key := range source.Keys() {
-		sourceValue, _ := source.Get(key)
-		targetValue, err := section.Get(key)
+// resolveDereferenced will resolve the value at path, following it if it is a Reference
+func (section *Section) resolveDereferenced(path string) (Value, error) {
+	value, err := section.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	return dereference(value)
+}
 
-		// not found, so add it
-		if err != nil {
-			section.Set(key, sourceValue)
-			continue
-		}
+// ResolveBoolean will try to resolve the value at path (e.g. "primary.sub.enabled") as a bool
+// will respond with an error if the path cannot be resolved or the value cannot be converted to a bool
+func (section *Section) ResolveBoolean(path string) (bool, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return false, err
+	}
+	converted, err := toBoolean(value, section.strictTypes())
+	return converted, section.withPath(err, path)
+}
 
-		// found existing one and it's type SECTION, merge it
-		if targetValue.GetType() == SECTION {
-			// Source value have to be SECTION type here
-			if sourceValue.GetType() != SECTION {
-				return fmt.Errorf("source (%v) and target (%v) type doesn't match: %v",
-					sourceValue.GetType(),
-					targetValue.GetType(),
-					key)
-			}
+// ResolveFloat will try to resolve the value at path (e.g. "primary.sub.ratio") as a float64
+// will respond with an error if the path cannot be resolved or the value cannot be converted to a float64
+func (section *Section) ResolveFloat(path string) (float64, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return float64(0), err
+	}
+	converted, err := toFloat(value, section.strictTypes())
+	return converted, section.withPath(err, path)
+}
 
-			if err = targetValue.(*Section).Merge(sourceValue.(*Section)); err != nil {
-				return err
+// ResolveInteger will try to resolve the value at path (e.g. "primary.sub.port") as a int64
+// will respond with an error if the path cannot be resolved or the value cannot be converted to a int64
+func (section *Section) ResolveInteger(path string) (int64, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return int64(0), err
+	}
+	converted, err := toInteger(value, section.strictTypes())
+	return converted, section.withPath(err, path)
+}
+
+// ResolveList will try to resolve the value at path (e.g. "primary.sub.hosts") as a List
+// will respond with an error if the path cannot be resolved or the value is not a List
+func (section *Section) ResolveList(path string) (*List, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return nil, err
+	}
+	list, err := toList(value)
+	return list, section.withPath(err, path)
+}
+
+// ResolveSection will try to resolve the value at path (e.g. "primary.sub") as a Section
+// will respond with an error if the path cannot be resolved or the value is not a Section
+func (section *Section) ResolveSection(path string) (*Section, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return nil, err
+	}
+	child, err := toSection(value)
+	return child, section.withPath(err, path)
+}
+
+// ResolveString will try to resolve the value at path (e.g. "primary.sub.key") as a string
+// will respond with an error if the path cannot be resolved or the value cannot be converted to a string
+func (section *Section) ResolveString(path string) (string, error) {
+	value, err := section.resolveDereferenced(path)
+	if err != nil {
+		return "", err
+	}
+	converted, err := toString(value, section.strictTypes())
+	return converted, section.withPath(err, path)
+}
+
+// ValidateReferences will try to resolve every Reference in this Section and all of it's
+// underlying Lists and Sections, responding with a ReferenceErrors containing every dangling
+// reference and every reference cycle found, or nil if all References can be resolved
+func (section *Section) ValidateReferences() error {
+	var errs ReferenceErrors
+	cycles := make(map[string]bool)
+
+	var validate func(value Value)
+	validate = func(value Value) {
+		switch value := value.(type) {
+		case *Section:
+			for _, key := range value.Keys() {
+				child, _ := value.Get(key)
+				validate(child)
+			}
+		case *List:
+			for _, child := range value.GetValues() {
+				validate(child)
+			}
+		case *Reference:
+			_, err := value.Resolve()
+			if err == nil {
+				return
 			}
 
-			continue
+			refErr := err.(*ReferenceError)
+			if len(refErr.Cycle) > 0 {
+				// Every reference in a cycle will report the same cycle, only report it once
+				members := make([]string, len(refErr.Cycle)-1)
+				copy(members, refErr.Cycle)
+				sort.Strings(members)
+				cycle := strings.Join(members, " ")
+				if cycles[cycle] {
+					return
+				}
+				cycles[cycle] = true
+			} else if refErr.reference != value {
+				// Only report dangling references where they are defined, not where they are referenced from
+				return
+			}
+			errs = append(errs, refErr)
 		}
+	}
+	validate(section)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Materialize will create a deep copy of this Section where every Reference, including
+// those in Lists and nested Sections, is replaced by a copy of the value it refers to.
+// Will respond with a *ReferenceError if any Reference cannot be resolved
+func (section *Section) Materialize() (*Section, error) {
+	materialized, _, err := section.MaterializeWithOrigins()
+	return materialized, err
+}
 
-		// found existing one, update it
-		if err = targetValue.UpdateValue(sourceValue.GetValue()); err != nil {
-			return fmt.Errorf("%v: %v", err, key)
+// MaterializeWithOrigins will create a deep copy of this Section like `Materialize` and will
+// also respond with a map from the path of every materialized Reference to the path of the
+// value it referred to (e.g. "secondary.global_reference" => "global")
+func (section *Section) MaterializeWithOrigins() (*Section, map[string]string, error) {
+	m := &materializer{
+		origins: make(map[string]string),
+	}
+	value, err := m.materialize(section, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value.(*Section), m.origins, nil
+}
+
+type materializer struct {
+	origins map[string]string
+	// The Sections currently being copied, used to detect references to a containing Section
+	sections []*Section
+}
+
+func (m *materializer) materialize(value Value, parent *Section, path []pathSegment) (Value, error) {
+	switch value := value.(type) {
+	case *Reference:
+		target, last, err := value.resolve()
+		if err != nil {
+			return nil, err
 		}
+		origin := absolutePath(last.section, last.name)
+		for _, current := range m.sections {
+			if Value(current) == target {
+				return nil, &ReferenceError{
+					Name:      value.name,
+					Position:  value.position,
+					Err:       fmt.Errorf("cannot materialize a reference to '%s' which contains it", origin),
+					reference: value,
+				}
+			}
+		}
+		m.origins[formatPath(path)] = origin
+		return m.materialize(target, parent, path)
+	case *Section:
+		m.sections = append(m.sections, value)
+		copied := newChildSection(parent)
+		copied.comments = append(copied.comments, value.comments...)
+		copied.includes = append(copied.includes, value.includes...)
+		for key, comments := range value.keyComments {
+			copied.keyComments[key] = &KeyComments{
+				Leading:  append(make([]string, 0), comments.Leading...),
+				Trailing: comments.Trailing,
+			}
+		}
+		for key, definitions := range value.definitions {
+			copied.definitions[key] = append(make([]Position, 0), definitions...)
+		}
+		for _, key := range value.OrderedKeys() {
+			child := value.values[key]
+			childPath := append(append([]pathSegment{}, path...), pathSegment{key: key})
+			materialized, err := m.materialize(child, copied, childPath)
+			if err != nil {
+				return nil, err
+			}
+			copied.setValue(key, materialized)
+		}
+		m.sections = m.sections[:len(m.sections)-1]
+		return copied, nil
+	case *List:
+		copied := NewList()
+		for i, child := range value.values {
+			childPath := append(append([]pathSegment{}, path...), pathSegment{index: i, isIndex: true})
+			materialized, err := m.materialize(child, parent, childPath)
+			if err != nil {
+				return nil, err
+			}
+			copied.Append(materialized)
+		}
+		return copied, nil
+	case *Primative:
+		return newPrimative(value.valueType, value.value), nil
 	}
-	return nil
+
+	return nil, fmt.Errorf("cannot materialize value of type %s", value.GetType())
+}
+
+// Merge merges the given section to current section. Settings from source
+// section overwites the values in the current section and Lists from source
+// section replace the Lists in the current section, see `MergeWithOptions`
+//
+// Keys which already exist keep their position in the current section and keys
+// which only exist in the source section are added after them, in the order
+// they were declared in the source section
+func (section *Section) Merge(source *Section) error {
+	return section.MergeWithOptions(source, MergeOptions{})
 }
 
 // ToJSON will convert this Section and all it's underlying values and Sections
@@ -359,6 +1311,86 @@ func (section *Section) ToJSON() ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// ToOrderedJSON will convert this Section and all it's underlying values and Sections
+// into JSON as a []byte, keys are written in the order they were declared in, see `OrderedKeys`
+func (section *Section) ToOrderedJSON() ([]byte, error) {
+	return EncodeJSON(section)
+}
+
+// EncodeJSON will convert any value into JSON, References are resolved (a Reference which cannot
+// be resolved is null) and the keys of Sections are written in declaration order, see `Section.ToOrderedJSON`
+func EncodeJSON(value Value) ([]byte, error) {
+	encoder := &orderedEncoder{}
+	if err := encoder.encode(value); err != nil {
+		return nil, err
+	}
+	return encoder.buffer.Bytes(), nil
+}
+
+// orderedEncoder writes values as JSON, writing the keys of Sections in declaration order
+type orderedEncoder struct {
+	buffer bytes.Buffer
+	// sections is the stack of Sections currently being written
+	sections []*Section
+}
+
+func (e *orderedEncoder) encode(value Value) error {
+	switch value := value.(type) {
+	case *Section:
+		for _, section := range e.sections {
+			if section == value {
+				return errors.New("cannot encode a reference to a section which contains it")
+			}
+		}
+		e.sections = append(e.sections, value)
+		e.buffer.WriteByte('{')
+		for i, key := range value.OrderedKeys() {
+			if i > 0 {
+				e.buffer.WriteByte(',')
+			}
+			encoded, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			e.buffer.Write(encoded)
+			e.buffer.WriteByte(':')
+			if err = e.encode(value.values[key]); err != nil {
+				return err
+			}
+		}
+		e.buffer.WriteByte('}')
+		e.sections = e.sections[:len(e.sections)-1]
+		return nil
+	case *List:
+		e.buffer.WriteByte('[')
+		for i, element := range value.values {
+			if i > 0 {
+				e.buffer.WriteByte(',')
+			}
+			if err := e.encode(element); err != nil {
+				return err
+			}
+		}
+		e.buffer.WriteByte(']')
+		return nil
+	case *Reference:
+		resolved, err := value.Resolve()
+		if err != nil {
+			// Unresolvable references have a null value, the same as `Reference.GetValue`
+			e.buffer.WriteString("null")
+			return nil
+		}
+		return e.encode(resolved)
+	}
+
+	encoded, err := json.Marshal(value.GetValue())
+	if err != nil {
+		return err
+	}
+	e.buffer.Write(encoded)
+	return nil
+}
+
 // ToMap will convert this Section and all it's underlying values and Sections into
 // a map[string]interface{}
 func (section *Section) ToMap() map[string]interface{} {
@@ -3,6 +3,7 @@ package forge_test
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/brettlangdon/forge"
@@ -151,3 +152,951 @@ prod = "I'm prod value"
 		t.Error(err)
 	}
 }
+
+func TestSplitPath(t *testing.T) {
+	t.Parallel()
+
+	parts := forge.SplitPath("a.10\\.0\\.0\\.1.back\\\\slash")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 path parts, got %v", parts)
+	}
+	if parts[0] != "a" || parts[1] != "10.0.0.1" || parts[2] != "back\\slash" {
+		t.Errorf("unexpected path parts %v", parts)
+	}
+
+	path := forge.JoinPath(parts...)
+	if path != "a.10\\.0\\.0\\.1.back\\\\slash" {
+		t.Errorf("unexpected joined path %s", path)
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+valid = other
+other = "value"
+missing = nothing.here
+list = [valid, .nowhere]
+a = b
+b = c
+c = a
+section {
+  self = .self
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = settings.ValidateReferences()
+	if err == nil {
+		t.Fatal("expected ValidateReferences to report errors")
+	}
+
+	errs := err.(forge.ReferenceErrors)
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 reference errors, got %d: %v", len(errs), errs)
+	}
+
+	expected := []string{
+		"reference 'b' at line 6 column 5 is part of a cycle: a -> b -> c -> a",
+		"reference '.nowhere' at line 5 column 16 could not be resolved: value 'nowhere' does not exist",
+		"reference 'nothing.here' at line 4 column 11 could not be resolved: value 'nothing.here' does not exist, could not find 'nothing'",
+		"reference '.self' at line 10 column 10 is part of a cycle: section.self -> section.self",
+	}
+	for i, msg := range expected {
+		if errs[i].Error() != msg {
+			t.Errorf("expected '%s' got '%s'", msg, errs[i].Error())
+		}
+	}
+
+	value, _ := settings.Get("missing")
+	_, err = value.(*forge.Reference).Resolve()
+	if _, ok := err.(*forge.ReferenceError); ok == false {
+		t.Errorf("expected a *ReferenceError, got %v", err)
+	}
+	if value.GetValue() != nil {
+		t.Errorf("expected a dangling reference value to be nil, got %v", value.GetValue())
+	}
+
+	value, _ = settings.Get("a")
+	if value.GetValue() != nil {
+		t.Errorf("expected a cyclic reference value to be nil, got %v", value.GetValue())
+	}
+
+	valid, err := forge.ParseString("a = b\nb = c\nc = 'value'\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = valid.ValidateReferences(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+global = "global value"
+primary {
+  host = "primary.local"
+  sub { key = ..host }
+}
+secondary {
+  global_reference = global
+  hosts = [primary.host, "other.local"]
+  primary_copy = primary
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	materialized, origins, err := settings.MaterializeWithOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var check func(value forge.Value, path string)
+	check = func(value forge.Value, path string) {
+		switch value := value.(type) {
+		case *forge.Reference:
+			t.Errorf("expected no references, found one at %s", path)
+		case *forge.Section:
+			for _, key := range value.Keys() {
+				child, _ := value.Get(key)
+				check(child, path+"."+key)
+			}
+		case *forge.List:
+			for _, child := range value.GetValues() {
+				check(child, path+"[]")
+			}
+		}
+	}
+	check(materialized, "")
+
+	value, _ := materialized.Resolve("secondary.primary_copy.sub.key")
+	if value.GetValue() != "primary.local" {
+		t.Errorf("expected 'primary.local' got %v", value.GetValue())
+	}
+	value, _ = materialized.Resolve("secondary.hosts[0]")
+	if value.GetValue() != "primary.local" {
+		t.Errorf("expected 'primary.local' got %v", value.GetValue())
+	}
+
+	expected := map[string]string{
+		"primary.sub.key":                "primary.host",
+		"secondary.global_reference":     "global",
+		"secondary.hosts[0]":             "primary.host",
+		"secondary.primary_copy":         "primary",
+		"secondary.primary_copy.sub.key": "primary.host",
+	}
+	if len(origins) != len(expected) {
+		t.Errorf("expected %d origins got %v", len(expected), origins)
+	}
+	for path, origin := range expected {
+		if origins[path] != origin {
+			t.Errorf("expected origin of %s to be '%s' got '%s'", path, origin, origins[path])
+		}
+	}
+
+	// The materialized copy must not share values with the original
+	materialized.SetString("global", "changed")
+	original, _ := settings.GetString("global")
+	if original != "global value" {
+		t.Errorf("expected original to be unchanged, got '%s'", original)
+	}
+}
+
+func TestMaterializeErrors(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString("a = missing\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = settings.Materialize(); err == nil {
+		t.Error("expected an error materializing a dangling reference")
+	}
+
+	settings, err = forge.ParseString("a { b = ..a }\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = settings.Materialize(); err == nil {
+		t.Error("expected an error materializing a reference to a containing section")
+	}
+}
+
+func TestGettersFollowReferences(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+global = "global value"
+number = 50
+section { enabled = true }
+global_reference = global
+chained = global_reference
+number_reference = number
+float_reference = number
+enabled_reference = section.enabled
+section_reference = section
+list = [1, 2]
+list_reference = list
+references = [global, number_reference, section.enabled]
+missing_reference = missing
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str, err := settings.GetString("chained")
+	if err != nil || str != "global value" {
+		t.Errorf("expected 'global value' got '%s' (%v)", str, err)
+	}
+	integer, err := settings.GetInteger("number_reference")
+	if err != nil || integer != 50 {
+		t.Errorf("expected 50 got %d (%v)", integer, err)
+	}
+	float, err := settings.GetFloat("float_reference")
+	if err != nil || float != 50 {
+		t.Errorf("expected 50 got %f (%v)", float, err)
+	}
+	boolean, err := settings.GetBoolean("enabled_reference")
+	if err != nil || boolean != true {
+		t.Errorf("expected true got %v (%v)", boolean, err)
+	}
+	if _, err = settings.GetSection("section_reference"); err != nil {
+		t.Error(err)
+	}
+
+	list, err := settings.GetList("list_reference")
+	if err != nil || list.Length() != 2 {
+		t.Errorf("expected list of length 2 (%v)", err)
+	}
+
+	list, err = settings.GetList("references")
+	if err != nil {
+		t.Fatal(err)
+	}
+	str, err = list.GetString(0)
+	if err != nil || str != "global value" {
+		t.Errorf("expected 'global value' got '%s' (%v)", str, err)
+	}
+	integer, err = list.GetInteger(1)
+	if err != nil || integer != 50 {
+		t.Errorf("expected 50 got %d (%v)", integer, err)
+	}
+	boolean, err = list.GetBoolean(2)
+	if err != nil || boolean != true {
+		t.Errorf("expected true got %v (%v)", boolean, err)
+	}
+
+	_, err = settings.GetString("missing_reference")
+	if _, ok := err.(*forge.ReferenceError); ok == false {
+		t.Errorf("expected a *ReferenceError got %v", err)
+	}
+}
+
+func TestResolveTypedGetters(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+primary {
+  sub {
+    key = "value"
+    port = 8080
+    ratio = 0.5
+    enabled = true
+    hosts = ["a", "b"]
+    reference = .key
+  }
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str, err := settings.ResolveString("primary.sub.key")
+	if err != nil || str != "value" {
+		t.Errorf("expected 'value' got '%s' (%v)", str, err)
+	}
+	str, err = settings.ResolveString("primary.sub.reference")
+	if err != nil || str != "value" {
+		t.Errorf("expected 'value' got '%s' (%v)", str, err)
+	}
+	str, err = settings.ResolveString("primary.sub.hosts[1]")
+	if err != nil || str != "b" {
+		t.Errorf("expected 'b' got '%s' (%v)", str, err)
+	}
+	integer, err := settings.ResolveInteger("primary.sub.port")
+	if err != nil || integer != 8080 {
+		t.Errorf("expected 8080 got %d (%v)", integer, err)
+	}
+	float, err := settings.ResolveFloat("primary.sub.ratio")
+	if err != nil || float != 0.5 {
+		t.Errorf("expected 0.5 got %f (%v)", float, err)
+	}
+	boolean, err := settings.ResolveBoolean("primary.sub.enabled")
+	if err != nil || boolean != true {
+		t.Errorf("expected true got %v (%v)", boolean, err)
+	}
+	list, err := settings.ResolveList("primary.sub.hosts")
+	if err != nil || list.Length() != 2 {
+		t.Errorf("expected list of length 2 (%v)", err)
+	}
+	if _, err = settings.ResolveSection("primary.sub"); err != nil {
+		t.Error(err)
+	}
+
+	_, err = settings.ResolveString("primary.missing.key")
+	expected := "value 'primary.missing.key' does not exist, could not find 'primary.missing'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected '%s' got '%v'", expected, err)
+	}
+
+	_, err = settings.ResolveString("primary.sub.key.deeper")
+	expected = "cannot convert STRING value at 'primary.sub.key' to SECTION"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected '%s' got '%v'", expected, err)
+	}
+}
+
+func TestSliceGetters(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+first { name = "first" }
+second { name = "second" }
+strings = ["a", "b", first.name]
+integers = [1, 2, 3]
+floats = [0.5, 1.5]
+booleans = [true, false]
+mixed = ["a", 1, true]
+sections = [first, second]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strs, err := settings.GetStrictStringSlice("strings")
+	if err != nil || len(strs) != 3 || strs[2] != "first" {
+		t.Errorf("expected [a b first] got %v (%v)", strs, err)
+	}
+	integers, err := settings.GetStrictIntegerSlice("integers")
+	if err != nil || len(integers) != 3 || integers[2] != 3 {
+		t.Errorf("expected [1 2 3] got %v (%v)", integers, err)
+	}
+	floats, err := settings.GetStrictFloatSlice("floats")
+	if err != nil || len(floats) != 2 || floats[1] != 1.5 {
+		t.Errorf("expected [0.5 1.5] got %v (%v)", floats, err)
+	}
+	booleans, err := settings.GetStrictBooleanSlice("booleans")
+	if err != nil || len(booleans) != 2 || booleans[1] != false {
+		t.Errorf("expected [true false] got %v (%v)", booleans, err)
+	}
+	sections, err := settings.GetSectionSlice("sections")
+	if err != nil || len(sections) != 2 {
+		t.Errorf("expected 2 sections (%v)", err)
+	}
+
+	// Non-strict getters convert mixed values
+	strs, err = settings.GetStringSlice("mixed")
+	if err != nil || len(strs) != 3 || strs[1] != "1" || strs[2] != "True" {
+		t.Errorf("expected [a 1 True] got %v (%v)", strs, err)
+	}
+	floats, err = settings.GetFloatSlice("integers")
+	if err != nil || len(floats) != 3 || floats[0] != 1 {
+		t.Errorf("expected [1 2 3] got %v (%v)", floats, err)
+	}
+
+	// Strict getters reject mixed values
+	_, err = settings.GetStrictStringSlice("mixed")
+	if err == nil || err.Error() != "cannot convert INTEGER value at 'mixed[1]' to STRING" {
+		t.Errorf("expected a strict type error got %v", err)
+	}
+	if _, err = settings.GetStrictFloatSlice("integers"); err == nil {
+		t.Error("expected a strict type error for integers as floats")
+	}
+	if _, err = settings.GetSectionSlice("strings"); err == nil {
+		t.Error("expected an error for strings as sections")
+	}
+}
+
+func TestSectionStrictTypes(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+primary {
+  flag = "false"
+  port = 8080
+  hosts = ["a", 1]
+  flags = ["false"]
+  nested = [["false"]]
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary, err := settings.GetSection("primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without strict types values are converted
+	flag, err := primary.GetBoolean("flag")
+	if err != nil || flag != true {
+		t.Errorf("expected true got %v (%v)", flag, err)
+	}
+
+	settings.SetStrictTypes(true)
+
+	_, err = primary.GetBoolean("flag")
+	if err == nil || err.Error() != "cannot convert STRING value at 'primary.flag' to BOOLEAN" {
+		t.Errorf("expected a *TypeError got %v", err)
+	}
+	_, err = settings.ResolveString("primary.port")
+	typeErr, ok := err.(*forge.TypeError)
+	if ok == false {
+		t.Fatalf("expected a *TypeError got %v", err)
+	}
+	if typeErr.Path != "primary.port" || typeErr.Want != forge.STRING || typeErr.Got != forge.INTEGER {
+		t.Errorf("unexpected *TypeError %#v", typeErr)
+	}
+	_, err = primary.GetStringSlice("hosts")
+	if err == nil || err.Error() != "cannot convert INTEGER value at 'primary.hosts[1]' to STRING" {
+		t.Errorf("expected a *TypeError got %v", err)
+	}
+
+	port, err := primary.GetFloat("port")
+	if err != nil || port != 8080 {
+		t.Errorf("expected 8080 got %f (%v)", port, err)
+	}
+
+	// Lists taken from the Section are strict as well
+	flags, err := primary.GetList("flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = flags.GetBoolean(0); err == nil {
+		t.Error("expected a *TypeError from a list of a strict section")
+	}
+	if _, err = flags.Booleans(); err == nil {
+		t.Error("expected a *TypeError from a list of a strict section")
+	}
+	lists, err := primary.GetList("nested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := lists.GetList(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = nested.GetBoolean(0); err == nil {
+		t.Error("expected a *TypeError from a nested list of a strict section")
+	}
+}
+
+func TestResolveSuggestions(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+primary {
+  host = "localhost";
+  port = 5432;
+  sub { key = "value"; }
+}
+secondary { }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = settings.Resolve("primray.sub.key")
+	notFound, ok := err.(*forge.NotFoundError)
+	if ok == false {
+		t.Fatalf("expected a *NotFoundError got %v", err)
+	}
+	if notFound.Resolved != "" || notFound.Key != "primray" {
+		t.Errorf("expected to fail at 'primray' of the root section got %#v", notFound)
+	}
+	if len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "primary" {
+		t.Errorf("expected the suggestion 'primary' got %v", notFound.Suggestions)
+	}
+	expected := "value 'primray.sub.key' does not exist, could not find 'primray', did you mean 'primary'?"
+	if err.Error() != expected {
+		t.Errorf("expected '%s' got '%s'", expected, err.Error())
+	}
+
+	_, err = settings.Resolve("primary.prot")
+	notFound, ok = err.(*forge.NotFoundError)
+	if ok == false {
+		t.Fatalf("expected a *NotFoundError got %v", err)
+	}
+	if notFound.Resolved != "primary" || notFound.Key != "prot" || notFound.Missing != "primary.prot" {
+		t.Errorf("expected to fail at 'prot' of 'primary' got %#v", notFound)
+	}
+	if len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "port" {
+		t.Errorf("expected the suggestion 'port' got %v", notFound.Suggestions)
+	}
+
+	// Get suggests from the section it is called on
+	primary, err := settings.GetSection("primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = primary.Get("hots")
+	notFound, ok = err.(*forge.NotFoundError)
+	if ok == false {
+		t.Fatalf("expected a *NotFoundError got %v", err)
+	}
+	if notFound.Path != "primary.hots" || len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "host" {
+		t.Errorf("expected 'primary.hots' with the suggestion 'host' got %#v", notFound)
+	}
+
+	// Nothing close enough is not suggested
+	_, err = settings.Resolve("unrelated")
+	if err == nil || err.Error() != "value 'unrelated' does not exist" {
+		t.Errorf("expected no suggestions got %v", err)
+	}
+}
+
+func TestSectionOrderedKeys(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+zebra = 1;
+apple {
+  second = "b";
+  first = "a";
+  copy = .second;
+}
+mango = [true, null];
+zebra = 2;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := settings.OrderedKeys()
+	if strings.Join(keys, ",") != "zebra,apple,mango" {
+		t.Errorf("expected declaration order 'zebra,apple,mango' got %v", keys)
+	}
+	if strings.Join(settings.Keys(), ",") != "apple,mango,zebra" {
+		t.Errorf("expected sorted keys 'apple,mango,zebra' got %v", settings.Keys())
+	}
+
+	data, err := settings.ToOrderedJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"zebra":2,"apple":{"second":"b","first":"a","copy":"b"},"mango":[true,null]}`
+	if string(data) != expected {
+		t.Errorf("expected '%s' got '%s'", expected, string(data))
+	}
+
+	// Existing keys keep their position, new keys follow in source order
+	source, err := forge.ParseString(`
+orange = 3;
+apple { third = "c"; first = "z"; }
+banana = 4;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = settings.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+	keys = settings.OrderedKeys()
+	if strings.Join(keys, ",") != "zebra,apple,mango,orange,banana" {
+		t.Errorf("expected merged order 'zebra,apple,mango,orange,banana' got %v", keys)
+	}
+	apple, err := settings.GetSection("apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = apple.OrderedKeys()
+	if strings.Join(keys, ",") != "second,first,copy,third" {
+		t.Errorf("expected merged order 'second,first,copy,third' got %v", keys)
+	}
+
+	materialized, err := settings.Materialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = materialized.OrderedKeys()
+	if strings.Join(keys, ",") != "zebra,apple,mango,orange,banana" {
+		t.Errorf("expected materialized order 'zebra,apple,mango,orange,banana' got %v", keys)
+	}
+}
+
+func TestToOrderedJSONSelfReference(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString("parent { self = parent; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = settings.ToOrderedJSON(); err == nil {
+		t.Error("expected an error encoding a reference to a containing section")
+	}
+}
+
+func TestSectionDelete(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+# Leading comment
+primary {
+  host = "localhost";
+  hosts = ["a", "b", "c"];
+}
+secondary = 1;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = settings.Delete("primary.host"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = settings.Resolve("primary.host"); err == nil {
+		t.Error("expected 'primary.host' to be deleted")
+	}
+
+	if err = settings.Delete("primary.hosts[1]"); err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := settings.ResolveList("primary.hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strs, err := hosts.Strings()
+	if err != nil || len(strs) != 2 || strs[0] != "a" || strs[1] != "c" {
+		t.Errorf("expected [a c] got %v (%v)", strs, err)
+	}
+	err = settings.Delete("primary.hosts[5]")
+	if indexErr, ok := err.(*forge.IndexError); ok == false || indexErr.Path != "primary.hosts" {
+		t.Errorf("expected an *IndexError for 'primary.hosts' got %v", err)
+	}
+
+	if err = settings.Delete("primary"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(settings.OrderedKeys(), ",") != "secondary" {
+		t.Errorf("expected only 'secondary' to remain got %v", settings.OrderedKeys())
+	}
+	if _, err = settings.GetKeyComments("primary"); err == nil {
+		t.Error("expected the comments of 'primary' to be deleted")
+	}
+
+	if _, ok := settings.Delete("missing").(*forge.NotFoundError); ok == false {
+		t.Error("expected a *NotFoundError deleting a missing value")
+	}
+	if _, ok := settings.Delete("secondary.key").(*forge.TypeError); ok == false {
+		t.Error("expected a *TypeError deleting through a non-section value")
+	}
+}
+
+func TestSectionRename(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+first = 1;
+second = 2;  # Trailing comment
+third = 3;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = settings.Rename("second", "middle"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(settings.OrderedKeys(), ",") != "first,middle,third" {
+		t.Errorf("expected 'first,middle,third' got %v", settings.OrderedKeys())
+	}
+	value, err := settings.GetInteger("middle")
+	if err != nil || value != 2 {
+		t.Errorf("expected 2 got %d (%v)", value, err)
+	}
+	comments, err := settings.GetKeyComments("middle")
+	if err != nil || comments.Trailing != " Trailing comment" {
+		t.Errorf("expected the comments to be renamed got %v (%v)", comments, err)
+	}
+
+	if err = settings.Rename("first", "third"); err == nil {
+		t.Error("expected an error renaming to an existing key")
+	}
+	if _, ok := settings.Rename("missing", "other").(*forge.NotFoundError); ok == false {
+		t.Error("expected a *NotFoundError renaming a missing key")
+	}
+}
+
+func TestSectionMove(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+# Leading comment
+old {
+  host = "localhost";
+  url = .host;
+  hosts = ["a", "b"];
+}
+other = 1;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = settings.Move("old", "new.nested.db"); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Exists("old") {
+		t.Error("expected 'old' to be moved")
+	}
+	db, err := settings.ResolveSection("new.nested.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := settings.ResolveSection("new.nested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.GetParent() != parent {
+		t.Error("expected the parent of the moved section to be updated")
+	}
+	comments, err := parent.GetKeyComments("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments.Leading) != 1 || comments.Leading[0] != " Leading comment" {
+		t.Errorf("expected the comments to be moved got %#v", comments)
+	}
+	url, err := settings.ResolveString("new.nested.db.url")
+	if err != nil || url != "localhost" {
+		t.Errorf("expected local references to follow the section got %s (%v)", url, err)
+	}
+
+	if err = settings.Move("new.nested.db.hosts[0]", "first_host"); err != nil {
+		t.Fatal(err)
+	}
+	host, err := settings.GetString("first_host")
+	if err != nil || host != "a" {
+		t.Errorf("expected 'a' got %s (%v)", host, err)
+	}
+
+	if err = settings.Move("other", "first_host"); err == nil {
+		t.Error("expected an error moving to an existing key")
+	}
+	if err = settings.Move("new", "new.nested.inner"); err == nil {
+		t.Error("expected an error moving a section into itself")
+	}
+	if _, ok := settings.Move("other", "first_host.key").(*forge.TypeError); ok == false {
+		t.Error("expected a *TypeError moving through a non-section value")
+	}
+	if settings.Move("other", "") == nil || settings.Move("other", "new..key") == nil {
+		t.Error("expected an error moving to an empty key")
+	}
+	if settings.Exists("other") == false {
+		t.Error("expected a failed move to leave the source in place")
+	}
+}
+
+func TestSectionClone(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+host = "localhost";
+primary {
+  port = 5432;  # Trailing comment
+  url = .port;
+  global = host;
+  hosts = ["a", .port];
+  sub { parent_port = ..port; }
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloned := settings.Clone().(*forge.Section)
+	if cloned.Equal(settings) == false || settings.Equal(cloned) == false {
+		t.Fatal("expected the clone to be equal to the original")
+	}
+	if cloned.HasParent() {
+		t.Error("expected the clone to have no parent")
+	}
+
+	primary, err := cloned.GetSection("primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary.GetParent() != cloned {
+		t.Error("expected the parent of a cloned section to be the clone")
+	}
+	comments, err := primary.GetKeyComments("port")
+	if err != nil || comments.Trailing != " Trailing comment" {
+		t.Errorf("expected the comments to be cloned got %v (%v)", comments, err)
+	}
+
+	// References are resolved from the clone
+	cloned.SetString("host", "remote")
+	primary.SetInteger("port", 1234)
+	for path, expected := range map[string]int64{"primary.url": 1234, "primary.hosts[1]": 1234, "primary.sub.parent_port": 1234} {
+		value, err := cloned.ResolveInteger(path)
+		if err != nil || value != expected {
+			t.Errorf("expected %s to be %d got %d (%v)", path, expected, value, err)
+		}
+	}
+	global, err := cloned.ResolveString("primary.global")
+	if err != nil || global != "remote" {
+		t.Errorf("expected 'remote' got %s (%v)", global, err)
+	}
+
+	// The original is unchanged
+	port, err := settings.ResolveInteger("primary.url")
+	if err != nil || port != 5432 {
+		t.Errorf("expected 5432 got %d (%v)", port, err)
+	}
+	if cloned.Equal(settings) {
+		t.Error("expected the modified clone not to be equal to the original")
+	}
+}
+
+func TestMergeCopiesValues(t *testing.T) {
+	t.Parallel()
+
+	target, err := forge.ParseString("existing = 1;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := forge.ParseString(`
+added {
+  key = "value";
+  copy = .key;
+}
+local = .existing;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = target.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+	added, err := target.GetSection("added")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.GetParent() != target {
+		t.Error("expected the merged section to belong to the target")
+	}
+
+	// Changing the target does not change the source
+	added.SetString("key", "changed")
+	value, err := source.ResolveString("added.copy")
+	if err != nil || value != "value" {
+		t.Errorf("expected 'value' got %s (%v)", value, err)
+	}
+	value, err = target.ResolveString("added.copy")
+	if err != nil || value != "changed" {
+		t.Errorf("expected 'changed' got %s (%v)", value, err)
+	}
+
+	// Local references from the source are resolved from the target
+	local, err := target.GetInteger("local")
+	if err != nil || local != 1 {
+		t.Errorf("expected 1 got %d (%v)", local, err)
+	}
+}
+
+func TestSectionFreeze(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString(`
+host = "localhost";
+primary {
+  port = 5432;
+  hosts = ["a", "b"];
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Freeze()
+	if settings.IsFrozen() == false {
+		t.Fatal("expected the section to be frozen")
+	}
+
+	primary, err := settings.GetSection("primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := primary.GetList("hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := primary.Get("port")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := forge.NewSection()
+	source.SetString("host", "remote")
+	mutations := map[string]error{
+		"SetString":   settings.SetString("host", "remote"),
+		"SetInteger":  primary.SetInteger("port", 1),
+		"Set":         settings.Set("new", forge.NewNull()),
+		"SetNull":     settings.SetNull("host"),
+		"AddComment":  settings.AddComment("comment"),
+		"Delete":      settings.Delete("primary.hosts[0]"),
+		"Rename":      settings.Rename("host", "hostname"),
+		"Move":        settings.Move("host", "primary.host"),
+		"Merge":       settings.Merge(source),
+		"UpdateValue": port.UpdateValue(int64(1)),
+		"Append":      hosts.Append(forge.NewString("c")),
+	}
+	for name, err := range mutations {
+		if _, ok := err.(*forge.FrozenError); ok == false {
+			t.Errorf("expected %s to respond with a *FrozenError got %v", name, err)
+		}
+	}
+	if _, err := settings.CreateSection("new"); err == nil {
+		t.Error("expected CreateSection to respond with a *FrozenError")
+	}
+	settings.GetValue().(map[string]forge.Value)["new"] = forge.NewNull()
+	hosts.GetValues()[0] = forge.NewString("c")
+	if settings.Exists("new") {
+		t.Error("expected GetValue to respond with a copy")
+	}
+	if err, ok := settings.SetString("host", "remote").(*forge.FrozenError); ok == false || err.Path != "host" {
+		t.Errorf("expected a *FrozenError for 'host' got %v", err)
+	}
+	if err, ok := primary.SetInteger("port", 1).(*forge.FrozenError); ok == false || err.Path != "primary.port" {
+		t.Errorf("expected a *FrozenError for 'primary.port' got %v", err)
+	}
+
+	// Nothing was changed
+	expected, _ := forge.ParseString(`
+host = "localhost";
+primary {
+  port = 5432;
+  hosts = ["a", "b"];
+}
+`)
+	if settings.Equal(expected) == false {
+		t.Error("expected a frozen section not to change")
+	}
+
+	// A clone can be changed
+	cloned := settings.Clone().(*forge.Section)
+	if cloned.IsFrozen() {
+		t.Error("expected a clone not to be frozen")
+	}
+	if err = cloned.SetString("host", "remote"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,74 @@
+//go:build go1.19
+// +build go1.19
+
+package forge
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds a frozen Section which can be read and replaced from multiple goroutines.
+// Readers `Load` the current Section, which never changes once loaded, and writers either
+// `Swap` in a new Section or `Update` a copy of the current Section. The zero Store holds no
+// Section, use `NewStore`
+//
+//	store := forge.NewStore(settings)
+//	host, err := store.Load().ResolveString("primary.host")
+//	err = store.Update(func(settings *forge.Section) error {
+//		primary, err := settings.GetSection("primary")
+//		if err != nil {
+//			return err
+//		}
+//		return primary.SetString("host", "example.org")
+//	})
+type Store struct {
+	current atomic.Pointer[Section]
+	// DEV: Writers are serialized so an `Update` cannot lose a concurrent `Swap`
+	lock sync.Mutex
+}
+
+// NewStore will create a new Store holding settings, settings is frozen with `Section.Freeze`
+func NewStore(settings *Section) *Store {
+	store := &Store{}
+	store.Swap(settings)
+	return store
+}
+
+// Load will return the current Section, it is frozen and safe to read from multiple goroutines.
+// Every change to it responds with a *FrozenError, see `Section.Freeze`
+func (store *Store) Load() *Section {
+	return store.current.Load()
+}
+
+// Swap will freeze settings with `Section.Freeze` and replace the current Section with it,
+// responding with the previous Section. A nil settings is replaced with an empty Section
+func (store *Store) Swap(settings *Section) *Section {
+	if settings == nil {
+		settings = NewSection()
+	}
+	settings.Freeze()
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.current.Swap(settings)
+}
+
+// Update will call fn with a copy of the current Section and replace the current Section with the
+// copy once fn returns, readers never see a partially updated Section. Will respond with the error
+// from fn and keep the current Section if fn fails
+func (store *Store) Update(fn func(settings *Section) error) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	settings := NewSection()
+	if current := store.current.Load(); current != nil {
+		settings = current.Clone().(*Section)
+	}
+	if err := fn(settings); err != nil {
+		return err
+	}
+	settings.Freeze()
+	store.current.Store(settings)
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build go1.19
+// +build go1.19
+
+package forge_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/brettlangdon/forge"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	settings, err := forge.ParseString("host = \"localhost\";\nport = 80;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := forge.NewStore(settings)
+	if store.Load() != settings || settings.IsFrozen() == false {
+		t.Fatal("expected the store to hold the frozen settings")
+	}
+
+	err = store.Update(func(settings *forge.Section) error {
+		return settings.SetString("host", "example.org")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host, _ := store.Load().GetString("host"); host != "example.org" {
+		t.Errorf("expected host 'example.org', got '%s'", host)
+	}
+	if host, _ := settings.GetString("host"); host != "localhost" {
+		t.Errorf("expected the previous settings to be unchanged, got '%s'", host)
+	}
+	if store.Load().IsFrozen() == false {
+		t.Error("expected the updated settings to be frozen")
+	}
+
+	// A failed update keeps the current settings
+	current := store.Load()
+	failure := errors.New("failure")
+	err = store.Update(func(settings *forge.Section) error {
+		settings.SetInteger("port", 8080)
+		return failure
+	})
+	if err != failure || store.Load() != current {
+		t.Errorf("expected the failed update to be discarded, got %v", err)
+	}
+
+	replacement := forge.NewSection()
+	if previous := store.Swap(replacement); previous != current {
+		t.Error("expected Swap to respond with the previous settings")
+	}
+	if store.Load() != replacement || replacement.IsFrozen() == false {
+		t.Error("expected Swap to store the frozen settings")
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	t.Parallel()
+
+	store := forge.NewStore(forge.NewSection())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				store.Update(func(settings *forge.Section) error {
+					count, _ := settings.GetInteger("count")
+					return settings.SetInteger("count", count+1)
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				store.Load().GetInteger("count")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count, _ := store.Load().GetInteger("count"); count != 200 {
+		t.Errorf("expected 200 updates, got %d", count)
+	}
+}
@@ -1,5 +1,7 @@
 package forge
 
+import "fmt"
+
 // ValueType is an int type for representing the types of values forge can handle
 type ValueType int
 
@@ -60,4 +62,29 @@ type Value interface {
 	GetType() ValueType
 	GetValue() interface{}
 	UpdateValue(interface{}) error
+	// Clone will return a deep copy of the value which shares nothing with the original
+	Clone() Value
+	// Equal will return true if other has the same type and structure as the value
+	Equal(other Value) bool
+}
+
+// Position describes where in a config a value was defined, Line and Column start from 1
+// and Filename is empty when the config was not parsed from a file
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// IsValid will return true if this Position was set by the parser
+func (position Position) IsValid() bool {
+	return position.Line > 0
+}
+
+func (position Position) String() string {
+	str := fmt.Sprintf("line %d column %d", position.Line, position.Column)
+	if position.Filename != "" {
+		str = fmt.Sprintf("%s %s", position.Filename, str)
+	}
+	return str
 }
@@ -0,0 +1,230 @@
+package forge
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchEvent describes a reload of the config watched by a Watcher
+type WatchEvent struct {
+	// Settings is the newly parsed config, it is frozen (see `Section.Freeze`) and nil when Err is set
+	Settings *Section
+	// Changes are the changes from the previous config to Settings, see `Diff`
+	Changes []Change
+	// Err is the reason the config could not be parsed, the Watcher keeps the last config which parsed
+	Err error
+}
+
+// Watcher polls a config file, every file it includes and every file which newly matches one of its
+// include patterns for changes, and reparses the config when any of them change. Changes are debounced,
+// the config is only reparsed once the files have not changed for the debounce duration
+//
+//	watcher, err := forge.NewWatcher("/etc/app/app.cfg", time.Second, 100*time.Millisecond)
+//	watcher.Subscribe(func(event forge.WatchEvent) {
+//		if event.Err == nil {
+//			reconfigure(event.Settings)
+//		}
+//	})
+//	watcher.Start()
+//	defer watcher.Stop()
+type Watcher struct {
+	filename string
+	interval time.Duration
+	debounce time.Duration
+
+	lock        sync.Mutex
+	settings    *Section
+	files       []string
+	patterns    []string
+	snapshot    map[string]fileState
+	pending     bool
+	lastChange  time.Time
+	subscribers []func(WatchEvent)
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// fileState is what a Watcher compares to find out whether a file changed
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher will parse filename and create a Watcher which polls it every interval once started,
+// will respond with an error if filename cannot be parsed
+func NewWatcher(filename string, interval time.Duration, debounce time.Duration) (*Watcher, error) {
+	watcher := &Watcher{
+		filename:    filename,
+		interval:    interval,
+		debounce:    debounce,
+		subscribers: make([]func(WatchEvent), 0),
+	}
+
+	settings, files, patterns, err := parseWatchedFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	watcher.settings = settings
+	watcher.files = files
+	watcher.patterns = patterns
+	watcher.snapshot = watcher.takeSnapshot()
+	return watcher, nil
+}
+
+// parseWatchedFile will parse filename and respond with every file which was parsed
+// and every include pattern, including those in included files
+func parseWatchedFile(filename string) (*Section, []string, []string, error) {
+	// DEV: Read the whole file so it is closed before parsing, the Watcher parses it many times
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	parser := NewParser(bytes.NewReader(data))
+	parser.addFile(filename)
+	parser.curFile = filename
+	if err = parser.Parse(); err != nil {
+		return nil, nil, nil, err
+	}
+	// DEV: The config is shared with subscribers while the Watcher diffs it on the next reload
+	settings := parser.GetSettings()
+	settings.Freeze()
+	return settings, parser.files, parser.patterns, nil
+}
+
+// Settings will return the last config which was parsed successfully, it is frozen
+// (see `Section.Freeze`), use `Section.Clone` to get a copy which can be changed
+func (watcher *Watcher) Settings() *Section {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	return watcher.settings
+}
+
+// Subscribe will call fn with every reload of the config which changed a value and with every error
+// parsing the config. fn is called from the polling goroutine and must not call `Stop`
+func (watcher *Watcher) Subscribe(fn func(WatchEvent)) {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	watcher.subscribers = append(watcher.subscribers, fn)
+}
+
+// Start will start polling for changes in a new goroutine, it does nothing if already started
+func (watcher *Watcher) Start() {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	if watcher.stop != nil {
+		return
+	}
+	watcher.stop = make(chan struct{})
+	watcher.done = make(chan struct{})
+	go watcher.run(watcher.stop, watcher.done)
+}
+
+// Stop will stop polling for changes and wait for the polling goroutine to exit
+func (watcher *Watcher) Stop() {
+	watcher.lock.Lock()
+	stop, done := watcher.stop, watcher.done
+	watcher.stop, watcher.done = nil, nil
+	watcher.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (watcher *Watcher) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			watcher.poll(now)
+		}
+	}
+}
+
+// poll will check the watched files for changes, reparsing the config once they stop changing
+func (watcher *Watcher) poll(now time.Time) {
+	watcher.lock.Lock()
+	snapshot := watcher.takeSnapshot()
+	if sameSnapshot(snapshot, watcher.snapshot) == false {
+		watcher.snapshot = snapshot
+		watcher.pending = true
+		watcher.lastChange = now
+	}
+	if watcher.pending == false || now.Sub(watcher.lastChange) < watcher.debounce {
+		watcher.lock.Unlock()
+		return
+	}
+	watcher.pending = false
+
+	var event WatchEvent
+	settings, files, patterns, err := parseWatchedFile(watcher.filename)
+	if err != nil {
+		event.Err = err
+	} else {
+		event.Settings = settings
+		event.Changes = Diff(watcher.settings, settings)
+		watcher.settings = settings
+		watcher.files = files
+		watcher.patterns = patterns
+		// The parsed files may have changed, e.g. a new include
+		watcher.snapshot = watcher.takeSnapshot()
+	}
+	subscribers := append(make([]func(WatchEvent), 0, len(watcher.subscribers)), watcher.subscribers...)
+	watcher.lock.Unlock()
+
+	if event.Err == nil && len(event.Changes) == 0 {
+		return
+	}
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
+
+// takeSnapshot will record the state of every parsed file and every file matching an include pattern
+func (watcher *Watcher) takeSnapshot() map[string]fileState {
+	filenames := append(make([]string, 0, len(watcher.files)), watcher.files...)
+	for _, pattern := range watcher.patterns {
+		matches, err := filepath.Glob(pattern)
+		if err == nil {
+			filenames = append(filenames, matches...)
+		}
+	}
+	sort.Strings(filenames)
+
+	snapshot := make(map[string]fileState)
+	for _, filename := range filenames {
+		info, err := os.Stat(filename)
+		if err != nil {
+			snapshot[filename] = fileState{}
+			continue
+		}
+		snapshot[filename] = fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	}
+	return snapshot
+}
+
+// sameSnapshot will return true if a and b have the same files in the same state
+func sameSnapshot(a map[string]fileState, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for filename, state := range a {
+		other, ok := b[filename]
+		if ok == false || state.exists != other.exists || state.size != other.size || state.modTime.Equal(other.modTime) == false {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package forge_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brettlangdon/forge"
+)
+
+// waitForEvent will wait for the next event sent to events or fail the test
+func waitForEvent(t *testing.T, events chan forge.WatchEvent) forge.WatchEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a watch event")
+	}
+	return forge.WatchEvent{}
+}
+
+func startWatcher(t *testing.T, filename string) (*forge.Watcher, chan forge.WatchEvent) {
+	watcher, err := forge.NewWatcher(filename, 10*time.Millisecond, 30*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan forge.WatchEvent, 10)
+	watcher.Subscribe(func(event forge.WatchEvent) {
+		events <- event
+	})
+	watcher.Start()
+	return watcher, events
+}
+
+func TestWatcherReload(t *testing.T) {
+	t.Parallel()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "main.cfg")
+	writeConfig(t, filename, "host = \"localhost\";\nport = 80;\n")
+	watcher, events := startWatcher(t, filename)
+	defer watcher.Stop()
+
+	writeConfig(t, filename, "host = \"example.org\";\nport = 80;\n")
+	event := waitForEvent(t, events)
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if len(event.Changes) != 1 || event.Changes[0].Path != "host" || event.Changes[0].Kind != forge.ChangeModified {
+		t.Fatalf("unexpected changes %v", event.Changes)
+	}
+	if host, _ := event.Settings.GetString("host"); host != "example.org" {
+		t.Errorf("expected host 'example.org', got '%s'", host)
+	}
+	if watcher.Settings() != event.Settings {
+		t.Error("expected Settings to return the reloaded config")
+	}
+	if event.Settings.IsFrozen() == false {
+		t.Error("expected the reloaded config to be frozen")
+	}
+}
+
+func TestWatcherParseError(t *testing.T) {
+	t.Parallel()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "main.cfg")
+	writeConfig(t, filename, "host = \"localhost\";\n")
+	watcher, events := startWatcher(t, filename)
+	defer watcher.Stop()
+	settings := watcher.Settings()
+
+	writeConfig(t, filename, "host = ;\n")
+	event := waitForEvent(t, events)
+	if event.Err == nil {
+		t.Fatal("expected a parse error")
+	}
+	if event.Settings != nil {
+		t.Error("expected no Settings with a parse error")
+	}
+	if watcher.Settings() != settings {
+		t.Error("expected the last good config to be kept")
+	}
+
+	writeConfig(t, filename, "host = \"example.org\";\n")
+	event = waitForEvent(t, events)
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if host, _ := watcher.Settings().GetString("host"); host != "example.org" {
+		t.Errorf("expected host 'example.org', got '%s'", host)
+	}
+}
+
+func TestWatcherIncludes(t *testing.T) {
+	t.Parallel()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "main.cfg")
+	writeConfig(t, filename, "host = \"localhost\";\ninclude \""+filepath.Join(dir, "*.inc")+"\";\n")
+	watcher, events := startWatcher(t, filename)
+	defer watcher.Stop()
+
+	// A file which newly matches the include pattern
+	included := filepath.Join(dir, "port.inc")
+	writeConfig(t, included, "port = 80;\n")
+	event := waitForEvent(t, events)
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if len(event.Changes) != 1 || event.Changes[0].Path != "port" || event.Changes[0].Kind != forge.ChangeAdded {
+		t.Fatalf("unexpected changes %v", event.Changes)
+	}
+
+	// The included file is now watched as well
+	writeConfig(t, included, "port = 8080;\n")
+	event = waitForEvent(t, events)
+	if port, _ := event.Settings.GetInteger("port"); port != 8080 {
+		t.Errorf("expected port 8080, got %d", port)
+	}
+}
+
+func TestWatcherBrokenInclude(t *testing.T) {
+	t.Parallel()
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "main.cfg")
+	included := filepath.Join(dir, "port.inc")
+	writeConfig(t, included, "port = 80;\n")
+	writeConfig(t, filename, "host = \"localhost\";\ninclude \""+filepath.Join(dir, "*.inc")+"\";\n")
+	watcher, events := startWatcher(t, filename)
+	defer watcher.Stop()
+	settings := watcher.Settings()
+
+	writeConfig(t, included, "broken = = =;\n")
+	event := waitForEvent(t, events)
+	if event.Err == nil {
+		t.Fatalf("expected a parse error, got changes %v", event.Changes)
+	}
+	if watcher.Settings() != settings {
+		t.Error("expected the last good config to be kept")
+	}
+	if port, _ := watcher.Settings().GetInteger("port"); port != 80 {
+		t.Errorf("expected port 80, got %d", port)
+	}
+}